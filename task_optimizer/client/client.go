@@ -0,0 +1,278 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is an error response of the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("task optimizer: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("task optimizer: %s: %s", http.StatusText(e.StatusCode), e.Message)
+}
+
+// Client calls the HTTP API of the task optimizer.
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	maxRetries     int
+	initialBackoff time.Duration
+	apiKey         string
+	namespace      string
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey sets the API key sent as a bearer token on every request.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithNamespace sets the namespace of the tasks, resources and webhooks of
+// the client, the default namespace if not set.
+func WithNamespace(namespace string) Option {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithRetries sets the number of retries of failed requests and the delay
+// before the first retry, doubled on each retry.
+func WithRetries(maxRetries int, initialBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.initialBackoff = initialBackoff
+	}
+}
+
+// New returns a client of the API served at baseURL, e.g.
+// "http://localhost:8080". By default failed requests are retried 3 times
+// from 100ms.
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		httpClient:     http.DefaultClient,
+		maxRetries:     3,
+		initialBackoff: 100 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if c.namespace != "" {
+		c.baseURL += "/namespaces/" + url.PathEscape(c.namespace)
+	}
+	return c
+}
+
+// AddTasks adds the tasks to the queue, or none of them if any is invalid.
+func (c *Client) AddTasks(ctx context.Context, tasks []Task) error {
+	return c.do(ctx, http.MethodPost, "/tasks", tasks, nil)
+}
+
+// ListTasks returns a page of the tasks matching the query, the next page is
+// listed with the same query and the NextCursor of the page as Cursor.
+func (c *Client) ListTasks(ctx context.Context, query TaskQuery) (TaskPage, error) {
+	var page TaskPage
+	header, err := c.exchange(ctx, http.MethodGet, "/tasks"+query.encode(), nil, nil, &page)
+	page.ETag = header.Get("ETag")
+	return page, err
+}
+
+// ListAllTasks follows the pages of the query and returns every matching task.
+func (c *Client) ListAllTasks(ctx context.Context, query TaskQuery) ([]Task, error) {
+	var tasks []Task
+	for {
+		page, err := c.ListTasks(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, page.Tasks...)
+		if page.NextCursor == "" {
+			return tasks, nil
+		}
+		query.Cursor = page.NextCursor
+	}
+}
+
+// encode returns the query string of the query, empty if it has no fields.
+func (q TaskQuery) encode() string {
+	values := url.Values{
+		"resource": q.Resources,
+		"client":   q.Clients,
+		"status":   q.Statuses,
+	}
+	for key, value := range map[string]string{"name": q.Name, "sort": q.Sort, "cursor": q.Cursor} {
+		if value != "" {
+			values.Set(key, value)
+		}
+	}
+	if q.MinProfit != nil {
+		values.Set("minProfit", strconv.FormatFloat(*q.MinProfit, 'g', -1, 64))
+	}
+	if q.MaxProfit != nil {
+		values.Set("maxProfit", strconv.FormatFloat(*q.MaxProfit, 'g', -1, 64))
+	}
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if encoded := values.Encode(); encoded != "" {
+		return "?" + encoded
+	}
+	return ""
+}
+
+// Execute removes from the queue the subset of compatible tasks with the
+// highest profit under the constraints of the request, and returns it.
+func (c *Client) Execute(ctx context.Context, request ExecutionRequest) (Execution, error) {
+	var execution Execution
+	var header http.Header
+	if request.IfMatch != "" {
+		header = http.Header{"If-Match": {request.IfMatch}}
+	}
+	_, err := c.exchange(ctx, http.MethodPost, "/tasks/execution", header, request, &execution)
+	return execution, err
+}
+
+// do sends the request and decodes the response into out, unless it's nil.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	_, err := c.exchange(ctx, method, path, nil, in, out)
+	return err
+}
+
+// exchange sends the request with the header and decodes the response into
+// out, unless it's nil, returning the header of the response. Requests are
+// retried on connection errors and on 5xx and 429 responses, except POST
+// requests, which are only retried when they were rejected before being
+// processed, with 429 or 503 responses.
+func (c *Client) exchange(ctx context.Context, method, path string, header http.Header, in, out any) (http.Header, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return nil, err
+		}
+	}
+	backoff := c.initialBackoff
+	for attempt := 0; ; attempt++ {
+		data, responseHeader, retryAfter, err := c.send(ctx, method, path, header, body)
+		if err == nil {
+			if out == nil {
+				return responseHeader, nil
+			}
+			return responseHeader, json.Unmarshal(data, out)
+		}
+		if attempt >= c.maxRetries || !retryable(method, err) {
+			return nil, err
+		}
+		delay := backoff
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+}
+
+// send sends the request once and returns the body and header of the
+// response, or the Retry-After delay of the response if it failed.
+func (c *Client) send(ctx context.Context, method, path string, header http.Header, body []byte) ([]byte, http.Header, time.Duration, error) {
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	c.authorize(request)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		apiError, retryAfter := responseError(response, data)
+		return nil, nil, retryAfter, apiError
+	}
+	return data, response.Header, 0, nil
+}
+
+// responseError returns the error of a failed response with the body data,
+// and its Retry-After delay.
+func responseError(response *http.Response, data []byte) (*Error, time.Duration) {
+	apiError := &Error{StatusCode: response.StatusCode}
+	var errorBody struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(data, &errorBody) == nil {
+		apiError.Message = errorBody.Error
+	} else {
+		apiError.Message = strings.TrimSpace(string(data))
+	}
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return apiError, retryAfter
+}
+
+func (c *Client) authorize(request *http.Request) {
+	if c.apiKey != "" {
+		request.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
+func retryable(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiError *Error
+	if !errors.As(err, &apiError) {
+		// The request may have been processed if the connection failed
+		// after it was sent.
+		return method != http.MethodPost
+	}
+	switch apiError.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return method != http.MethodPost
+	default:
+		return false
+	}
+}
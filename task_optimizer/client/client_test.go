@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		call         func(c *Client) error
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{"list retried on server error", []int{500, 502, 200}, func(c *Client) error {
+			_, err := c.ListTasks(context.Background(), TaskQuery{})
+			return err
+		}, 3, 0},
+		{"add retried on too many requests", []int{429, 200}, func(c *Client) error {
+			return c.AddTasks(context.Background(), []Task{{Name: "a"}})
+		}, 2, 0},
+		{"add not retried on server error", []int{500, 200}, func(c *Client) error {
+			return c.AddTasks(context.Background(), []Task{{Name: "a"}})
+		}, 1, 500},
+		{"execute not retried on conflict", []int{409}, func(c *Client) error {
+			_, err := c.Execute(context.Background(), ExecutionRequest{})
+			return err
+		}, 1, 409},
+		{"retries exhausted", []int{503, 503, 503}, func(c *Client) error {
+			_, err := c.Execute(context.Background(), ExecutionRequest{})
+			return err
+		}, 3, 503},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts.Add(1)-1]
+				w.WriteHeader(status)
+				switch {
+				case status >= 300:
+					json.NewEncoder(w).Encode(map[string]string{"error": "failed"})
+				case r.Method == http.MethodGet:
+					w.Write([]byte(`{"tasks": [], "total": 0}`))
+				case r.URL.Path == "/tasks/execution":
+					w.Write([]byte("{}"))
+				}
+			}))
+			defer server.Close()
+
+			err := tt.call(New(server.URL, WithRetries(2, time.Millisecond)))
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			var apiError *Error
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tt.wantStatus != 0 && (!errors.As(err, &apiError) || apiError.StatusCode != tt.wantStatus || apiError.Message != "failed"):
+				t.Errorf("error = %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestClient_Execute(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request ExecutionRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.EnergyBudget == nil || *request.EnergyBudget != 10 {
+			t.Errorf("unexpected request %+v, %v", request, err)
+		}
+		w.Write([]byte(`{"tasks": [{"id": 1, "name": "capture", "satellite": "sat-1"}], "energyUsed": 4}`))
+	}))
+	defer server.Close()
+
+	budget := 10.0
+	execution, err := New(server.URL).Execute(context.Background(), ExecutionRequest{EnergyBudget: &budget})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execution.Tasks) != 1 || execution.Tasks[0].Name != "capture" || execution.Tasks[0].Satellite != "sat-1" || execution.EnergyUsed != 4 {
+		t.Errorf("Execute() = %+v", execution)
+	}
+}
+
+func TestClient_IfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Header().Set("ETag", `"7"`)
+			w.Write([]byte(`{"tasks": [], "total": 0}`))
+			return
+		}
+		if r.Header.Get("If-Match") != `"7"` {
+			t.Errorf("If-Match = %q, want \"7\"", r.Header.Get("If-Match"))
+		}
+		w.WriteHeader(http.StatusPreconditionFailed)
+		w.Write([]byte(`{"error": "task list version mismatch"}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	page, err := c.ListTasks(context.Background(), TaskQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Execute(context.Background(), ExecutionRequest{IfMatch: page.ETag})
+	var apiError *Error
+	if !errors.As(err, &apiError) || apiError.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("Execute() error = %v, want a 412 error", err)
+	}
+}
+
+func TestClient_Namespace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/namespaces/mission-a/tasks" {
+			t.Errorf("path = %s, want /namespaces/mission-a/tasks", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	if err := New(server.URL, WithNamespace("mission-a")).AddTasks(context.Background(), []Task{{Name: "capture"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_ListAllTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer planner-key" {
+			t.Errorf("unexpected Authorization %q", r.Header.Get("Authorization"))
+		}
+		query := r.URL.Query()
+		if query.Get("sort") != "-profit" || query["resource"][0] != "camera" || query.Get("limit") != "1" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch query.Get("cursor") {
+		case "":
+			w.Write([]byte(`{"tasks": [{"id": 2, "name": "b"}], "total": 2, "nextCursor": "c1"}`))
+		case "c1":
+			w.Write([]byte(`{"tasks": [{"id": 1, "name": "a"}], "total": 2}`))
+		default:
+			t.Errorf("unexpected cursor %q", query.Get("cursor"))
+		}
+	}))
+	defer server.Close()
+
+	tasks, err := New(server.URL, WithAPIKey("planner-key")).ListAllTasks(context.Background(), TaskQuery{Resources: []string{"camera"}, Sort: "-profit", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != 2 || tasks[1].ID != 1 {
+		t.Errorf("ListAllTasks() = %+v", tasks)
+	}
+}
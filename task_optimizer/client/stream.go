@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	MediaTypeNDJSON = "application/x-ndjson"
+	MediaTypeCSV    = "text/csv"
+)
+
+// ImportTasks streams the tasks read from body, encoded as mediaType,
+// MediaTypeNDJSON or MediaTypeCSV, and reports the lines that couldn't be
+// imported. The valid tasks are imported even if other lines are invalid.
+// Imports are never retried, since body can only be read once.
+func (c *Client) ImportTasks(ctx context.Context, mediaType string, body io.Reader) (ImportReport, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/tasks", body)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	request.Header.Set("Content-Type", mediaType)
+	c.authorize(request)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return ImportReport{}, err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		apiError, _ := responseError(response, data)
+		return ImportReport{}, apiError
+	}
+	var report ImportReport
+	err = json.Unmarshal(data, &report)
+	return report, err
+}
+
+// ExportTasks returns a stream of every task of the queue, encoded as format,
+// "ndjson" or "csv". The caller must close the stream.
+func (c *Client) ExportTasks(ctx context.Context, format string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/tasks/export?"+url.Values{"format": {format}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.authorize(request)
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		defer response.Body.Close()
+		data, _ := io.ReadAll(response.Body)
+		apiError, _ := responseError(response, data)
+		return nil, apiError
+	}
+	return response.Body, nil
+}
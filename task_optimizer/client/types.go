@@ -0,0 +1,124 @@
+package client
+
+import "time"
+
+// The types mirror the schemas of the OpenAPI specification in
+// api/openapi.yaml.
+
+type Task struct {
+	ID              uint64     `json:"id,omitempty"`
+	Name            string     `json:"name"`
+	Client          string     `json:"client,omitempty"`
+	Resources       []string   `json:"resources"`
+	Profit          float64    `json:"profit"`
+	EffectiveProfit float64    `json:"effectiveProfit,omitempty"`
+	Decay           *Decay     `json:"decay,omitempty"`
+	Satellites      []string   `json:"satellites,omitempty"`
+	Energy          float64    `json:"energy,omitempty"`
+	Priority        string     `json:"priority,omitempty"`
+	SubmittedAt     *time.Time `json:"submittedAt,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	Pinned          bool       `json:"pinned,omitempty"`
+	Held            bool       `json:"held,omitempty"`
+	TemplateID      uint64     `json:"templateId,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	SubmittedBy     string     `json:"submittedBy,omitempty"`
+}
+
+// TaskQuery filters, sorts and paginates the listed tasks, its zero value
+// lists the first page of every task sorted by ID.
+type TaskQuery struct {
+	// Resources that the tasks must all claim.
+	Resources []string
+	// Name is a case insensitive substring of the name of the tasks.
+	Name      string
+	MinProfit *float64
+	MaxProfit *float64
+	Clients   []string
+	Statuses  []string
+	// Sort is id, profit or submittedAt, prefixed with - for descending order.
+	Sort string
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+	Limit  int
+}
+
+type ImportReport struct {
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+	// Errors holds the errors of the first failed lines.
+	Errors []ImportError `json:"errors"`
+}
+
+type ImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	// ETag is the version of the task list the page was read at, to execute
+	// the tasks only if the list didn't change with ExecutionRequest.IfMatch.
+	ETag string `json:"-"`
+}
+
+type Decay struct {
+	Type        string  `json:"type"`
+	RatePerHour float64 `json:"ratePerHour,omitempty"`
+	HalfLife    string  `json:"halfLife,omitempty"`
+	After       string  `json:"after,omitempty"`
+	Factor      float64 `json:"factor,omitempty"`
+}
+
+type ExecutionRequest struct {
+	EnergyBudget *float64  `json:"energyBudget,omitempty"`
+	Fairness     *Fairness `json:"fairness,omitempty"`
+	// IfMatch is the ETag of a TaskPage, so that the execution fails with a
+	// 412 error if the task list changed since the page was read.
+	IfMatch string `json:"-"`
+}
+
+type Fairness struct {
+	Policy          string             `json:"policy"`
+	MinClientShare  float64            `json:"minClientShare,omitempty"`
+	MaxClientProfit float64            `json:"maxClientProfit,omitempty"`
+	ClientWeights   map[string]float64 `json:"clientWeights,omitempty"`
+}
+
+type Assignment struct {
+	Task
+	Satellite string `json:"satellite,omitempty"`
+}
+
+type ClientShare struct {
+	Client string  `json:"client"`
+	Profit float64 `json:"profit"`
+	Tasks  int     `json:"tasks"`
+}
+
+type Penalty struct {
+	Tasks     [2]uint64 `json:"tasks"`
+	Resources []string  `json:"resources"`
+	Penalty   float64   `json:"penalty"`
+}
+
+type Deferral struct {
+	Task     Task      `json:"task"`
+	OutageID uint64    `json:"outageId"`
+	Resource string    `json:"resource"`
+	Until    time.Time `json:"until"`
+}
+
+type Execution struct {
+	Tasks        []Assignment  `json:"tasks"`
+	Clients      []ClientShare `json:"clients"`
+	Penalties    []Penalty     `json:"penalties"`
+	Deferred     []Deferral    `json:"deferred"`
+	PenaltyTotal float64       `json:"penaltyTotal"`
+	EnergyUsed   float64       `json:"energyUsed"`
+	EnergyBudget *float64      `json:"energyBudget,omitempty"`
+	EnergyMargin *float64      `json:"energyMargin,omitempty"`
+	FairnessMet  *bool         `json:"fairnessMet,omitempty"`
+}
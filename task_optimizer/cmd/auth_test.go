@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_Auth(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(auth.KeyFile{Keys: []auth.KeyEntry{
+		{Name: "planner", Role: "submitter", Key: "planner-key"},
+		{Name: "ops", Role: "operator", Key: "ops-key"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := newServeMux(namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil), health.NewChecker(), authenticator, handler.NewLimiter(handler.Limits{}, httpMetrics))
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		header     string
+		value      string
+		body       string
+		wantStatus int
+	}{
+		{"no key", http.MethodGet, "/tasks", "", "", "", http.StatusUnauthorized},
+		{"invalid key", http.MethodGet, "/tasks", "X-API-Key", "wrong", "", http.StatusUnauthorized},
+		{"submitter adds", http.MethodPost, "/tasks", "X-API-Key", "planner-key", `[{"name": "capture", "resources": ["camera"], "profit": 1}]`, http.StatusOK},
+		{"submitter can't pin", http.MethodPost, "/tasks", "X-API-Key", "planner-key", `[{"name": "calibration", "resources": ["camera"], "pinned": true}]`, http.StatusForbidden},
+		{"submitter can't hold templates", http.MethodPost, "/task-templates", "X-API-Key", "planner-key", `{"name": "daily", "interval": "24h", "task": {"name": "calibration", "resources": ["camera"], "held": true}}`, http.StatusForbidden},
+		{"submitter can't execute", http.MethodPost, "/tasks/execution", "X-API-Key", "planner-key", "", http.StatusForbidden},
+		{"operator executes with bearer token", http.MethodPost, "/tasks/execution", "Authorization", "Bearer ops-key", "", http.StatusOK},
+		{"operator can't add webhooks", http.MethodPost, "/webhooks", "Authorization", "Bearer ops-key", `{"url": "http://localhost"}`, http.StatusForbidden},
+		{"unknown namespace without key", http.MethodGet, "/namespaces/unknown/tasks", "", "", "", http.StatusUnauthorized},
+		{"unknown namespace", http.MethodGet, "/namespaces/unknown/tasks", "X-API-Key", "planner-key", "", http.StatusNotFound},
+		{"operator can't create namespaces", http.MethodPost, "/namespaces", "X-API-Key", "ops-key", `{"name": "mission-a"}`, http.StatusForbidden},
+		{"specification is public", http.MethodGet, "/openapi.yaml", "", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.header != "" {
+				request.Header.Set(tt.header, tt.value)
+			}
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	t.Run("tasks record their submitter", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`[{"name": "downlink", "resources": ["antenna"], "profit": 1}]`))
+		request.Header.Set("X-API-Key", "planner-key")
+		mux.ServeHTTP(httptest.NewRecorder(), request)
+		request = httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		request.Header.Set("X-API-Key", "planner-key")
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		var page dto.TaskPage
+		if err := json.NewDecoder(recorder.Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Tasks) != 1 || page.Tasks[0].SubmittedBy != "planner" {
+			t.Errorf("GET /tasks = %+v, want downlink submitted by planner", page.Tasks)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_Events(t *testing.T) {
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	server := httptest.NewServer(newServeMux(namespaces, health.NewChecker(), nil, handler.NewLimiter(handler.Limits{}, httpMetrics)))
+	defer server.Close()
+	addTask := func(name string) {
+		response, err := http.Post(server.URL+"/tasks", "application/json", strings.NewReader(`[{"name": "`+name+`", "resources": ["camera"], "profit": 1}]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+	}
+	// firstEventID opens a stream, adds a task and returns the ID of the
+	// first event streamed.
+	firstEventID := func(lastEventID string) string {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastEventID != "" {
+			request.Header.Set("Last-Event-ID", lastEventID)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		addTask("downlink")
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			if id, ok := strings.CutPrefix(scanner.Text(), "id: "); ok {
+				return id
+			}
+		}
+		t.Fatalf("no event streamed: %v", scanner.Err())
+		return ""
+	}
+
+	addTask("capture")
+	if id := firstEventID(""); id != "2" {
+		t.Errorf("new stream starts at event %s, want 2", id)
+	}
+	if id := firstEventID("0"); id != "1" {
+		t.Errorf("stream resumed after event 0 starts at event %s, want 1", id)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_Health(t *testing.T) {
+	checker := health.NewChecker()
+	storageErr := errors.New("storage unreachable")
+	checker.AddCheck("storage", func(ctx context.Context) error { return storageErr })
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	mux := newServeMux(namespaces, checker, nil, handler.NewLimiter(handler.Limits{}, httpMetrics))
+	get := func(path string, body any) int {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if err := json.NewDecoder(recorder.Body).Decode(body); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code
+	}
+
+	var healthDto dto.Health
+	if status := get("/healthz", &healthDto); status != http.StatusOK || healthDto.Status != dto.StatusOK {
+		t.Errorf("GET /healthz = %d %+v, want %d", status, healthDto, http.StatusOK)
+	}
+
+	tests := []struct {
+		name       string
+		prepare    func()
+		wantStatus int
+		wantChecks map[string]string
+	}{
+		{"starting", func() {}, http.StatusServiceUnavailable, map[string]string{"startup": "startup in progress", "storage": "storage unreachable"}},
+		{"storage unreachable", checker.SetStarted, http.StatusServiceUnavailable, map[string]string{"startup": "ok", "storage": "storage unreachable"}},
+		{"ready", func() { storageErr = nil }, http.StatusOK, map[string]string{"startup": "ok", "storage": "ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			var readiness dto.Readiness
+			status := get("/readyz", &readiness)
+			if status != tt.wantStatus {
+				t.Errorf("GET /readyz status = %d, want %d", status, tt.wantStatus)
+			}
+			for name, want := range tt.wantChecks {
+				if got := readiness.Checks[name]; got != want {
+					t.Errorf("check %s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+
+	var build dto.BuildInfo
+	if status := get("/version", &build); status != http.StatusOK || build.GoVersion != runtime.Version() || build.Commit == "" {
+		t.Errorf("GET /version = %d %+v", status, build)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_TaskCountLimit(t *testing.T) {
+	config := service.DefaultConfig()
+	config.MaxTasksPerRequest = 2
+	namespaces := namespace.NewRegistry(context.Background(), config, taskServiceMetrics, nil)
+	defaultNamespace, _ := namespaces.Get(namespace.Default)
+	taskService := defaultNamespace.Service
+	mux := newServeMux(namespaces, health.NewChecker(), nil, handler.NewLimiter(handler.Limits{}, httpMetrics))
+	task := `{"name": "capture", "resources": ["camera"], "profit": 1}`
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+		wantTasks   int
+	}{
+		{"array within limit", "application/json", "[" + task + "," + task + "]", http.StatusOK, 2},
+		{"array over limit", "application/json", "[" + task + "," + task + "," + task + "]", http.StatusRequestEntityTooLarge, 2},
+		{"stream over limit keeps the first tasks", "application/x-ndjson", task + "\n" + task + "\n" + task + "\n", http.StatusRequestEntityTooLarge, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+			if got := len(taskService.ListAllTasks()); got != tt.wantTasks {
+				t.Errorf("tasks = %d, want %d", got, tt.wantTasks)
+			}
+		})
+	}
+}
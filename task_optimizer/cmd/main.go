@@ -1,37 +1,150 @@
 package main
 
 import (
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"net/http"
+	"google.golang.org/grpc"
+	"io"
+	"net"
 	"os"
-	"task_optimizer/internal/controller"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/buildinfo"
+	"task_optimizer/internal/config"
 	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
 	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/pb"
+	"task_optimizer/internal/ratelimit"
+	"task_optimizer/internal/rpc"
 	"task_optimizer/internal/service"
+	"task_optimizer/internal/webhook"
 	"time"
 )
 
 func main() {
-	logFile, err := os.OpenFile("/logs/task_optimizer.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
 	if err != nil {
-		panic("error opening log file")
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	logOutput, closeLog := io.Writer(os.Stdout), func() {}
+	if cfg.Log.Output == config.LogFile {
+		logFile, err := openLogFile(cfg.Log.File)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error opening log file: %v\n", err)
+			os.Exit(1)
+		}
+		logOutput, closeLog = logFile, func() { logFile.Close() }
 	}
-	defer logFile.Close()
+	// The level was validated by config.Load.
+	level, _ := zerolog.ParseLevel(cfg.Log.Level)
+	zerolog.SetGlobalLevel(level)
 	zerolog.TimeFieldFormat = time.RFC3339
-	log.Logger = zerolog.New(logFile).With().Timestamp().Logger()
+	log.Logger = zerolog.New(logOutput).With().Timestamp().Logger()
+	build := buildinfo.Get()
+	metrics.NewBuildMetrics(build)
+	log.Info().Str("commit", build.Commit).Str("build_time", build.BuildTime).Str("go_version", build.GoVersion).Msg("starting")
+
+	var authenticator *auth.Authenticator
+	if cfg.APIKeys != "" {
+		if authenticator, err = auth.LoadKeyFile(cfg.APIKeys); err != nil {
+			log.Fatal().Err(err).Msg("error loading API keys")
+		}
+	} else {
+		log.Warn().Msg("authentication disabled, no API key file given")
+	}
+
+	serviceConfig := service.DefaultConfig()
+	serviceConfig.MaxTasksPerRequest = cfg.Limits.MaxTasks
+	serviceConfig.Solver = cfg.Solver.Algorithm
+	serviceConfig.SolverTimeout = cfg.Solver.Timeout
+	webhookMetrics := metrics.NewWebhookMetrics()
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	namespaces := namespace.NewRegistry(backgroundCtx, serviceConfig, metrics.NewTaskServiceMetricsVec(), func(ctx context.Context, taskService *service.TaskService) {
+		var background sync.WaitGroup
+		background.Add(2)
+		go func() {
+			defer background.Done()
+			taskService.RunReaper(ctx)
+		}()
+		go func() {
+			defer background.Done()
+			taskService.RunScheduler(ctx)
+		}()
+		webhook.NewDispatcher(taskService, webhook.DefaultConfig(), webhookMetrics).Run(ctx)
+		background.Wait()
+	})
 
-	taskService := service.NewTaskService(metrics.NewTaskServiceMetrics())
-	taskController := controller.NewTaskController(taskService)
+	limiter := handler.NewLimiter(handler.Limits{
+		RateLimit:    ratelimit.Limit{Rate: cfg.Limits.RateLimit, Burst: cfg.Limits.RateBurst},
+		MaxBodyBytes: cfg.Limits.MaxBodyBytes,
+	}, metrics.NewHTTPMetrics())
 
-	http.Handle("/metrics", promhttp.Handler())
+	checker := health.NewChecker()
+	checker.AddCheck("storage", func(ctx context.Context) error {
+		// Tasks are kept in memory, reachable as long as the namespaces are.
+		_, err := namespaces.Get(namespace.Default)
+		return err
+	})
 
-	http.HandleFunc("GET /tasks", handler.ToLoggedHandlerFunc(taskController.ListTasks))
-	http.HandleFunc("POST /tasks", handler.ToLoggedHandlerFunc(taskController.AddTasks))
-	http.HandleFunc("POST /tasks/execution", handler.ToLoggedHandlerFunc(taskController.GetHigherProfitTasks))
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	httpListener, err := net.Listen("tcp", cfg.HTTPAddr)
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", cfg.HTTPAddr).Msg("error listening for HTTP")
+	}
+	grpcListener, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		log.Fatal().Err(err).Str("addr", cfg.GRPCAddr).Msg("error listening for gRPC")
+	}
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rpc.UnaryAuthInterceptor(authenticator), rpc.UnaryLoggingInterceptor),
+		grpc.ChainStreamInterceptor(rpc.StreamAuthInterceptor(authenticator), rpc.StreamLoggingInterceptor),
+		// Stop waits for the canceled calls to roll back.
+		grpc.WaitForHandlers(true),
+	)
+	pb.RegisterTaskOptimizerServer(grpcServer, rpc.NewTaskServer(namespaces))
+
+	// Tasks are kept in memory, there is no state to recover on startup.
+	checker.SetStarted()
+	err = serve(httpListener, grpcListener, newServeMux(namespaces, checker, authenticator, limiter), grpcServer, signals, cfg.GracePeriod)
+	// No request is left, stop the reapers, schedulers and webhook
+	// dispatchers, and give the webhook deliveries in flight the grace
+	// period to complete. Tasks are kept in memory, there is no storage to
+	// flush.
+	stopBackground()
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), cfg.GracePeriod)
+	if err := namespaces.Wait(waitCtx); err != nil {
+		log.Warn().Msg("grace period over, abandoning the webhook deliveries in flight")
+	}
+	cancelWait()
+	if err != nil {
+		log.Err(err).Msg("shutdown after a server failure")
+		closeLog()
+		os.Exit(1)
+	}
+	log.Info().Msg("shutdown complete")
+	closeLog()
+}
 
-	if err := http.ListenAndServe(":8080", nil); err != nil {
-		log.Err(err).Send()
+// openLogFile opens the log file at path for appending, creating it and its
+// directory if missing.
+func openLogFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
 	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 }
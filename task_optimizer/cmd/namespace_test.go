@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_Namespaces(t *testing.T) {
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	mux := newServeMux(namespaces, health.NewChecker(), nil, handler.NewLimiter(handler.Limits{}, httpMetrics))
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder
+	}
+	tasks := func(path string) int {
+		var page dto.TaskPage
+		if err := json.NewDecoder(serve(http.MethodGet, path, "").Body).Decode(&page); err != nil {
+			t.Fatal(err)
+		}
+		return page.Total
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"create namespace", http.MethodPost, "/namespaces", `{"name": "mission-a"}`, http.StatusCreated},
+		{"create existing namespace", http.MethodPost, "/namespaces", `{"name": "mission-a"}`, http.StatusConflict},
+		{"create invalid namespace", http.MethodPost, "/namespaces", `{"name": "Mission A"}`, http.StatusBadRequest},
+		{"add tasks in namespace", http.MethodPost, "/namespaces/mission-a/tasks", `[{"name": "capture", "resources": ["camera"], "profit": 1}]`, http.StatusOK},
+		{"add tasks in default namespace", http.MethodPost, "/namespaces/default/tasks", `[{"name": "downlink", "resources": ["antenna"], "profit": 1}]`, http.StatusOK},
+		{"unknown namespace", http.MethodGet, "/namespaces/mission-b/tasks", "", http.StatusNotFound},
+		{"delete default namespace", http.MethodDelete, "/namespaces/default", "", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.method, tt.path, tt.body).Code; got != tt.wantStatus {
+				t.Errorf("status = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+
+	if got := tasks("/namespaces/mission-a/tasks"); got != 1 {
+		t.Errorf("tasks in mission-a = %d, want 1", got)
+	}
+	if got := tasks("/tasks"); got != 1 {
+		t.Errorf("tasks in the default namespace = %d, want 1", got)
+	}
+	execution := serve(http.MethodPost, "/namespaces/mission-a/tasks/execution", "")
+	var plan dto.Execution
+	if err := json.NewDecoder(execution.Body).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Tasks) != 1 || plan.Tasks[0].Name != "capture" {
+		t.Errorf("execution in mission-a = %+v, want capture only", plan.Tasks)
+	}
+
+	if got := serve(http.MethodDelete, "/namespaces/mission-a", "").Code; got != http.StatusOK {
+		t.Fatalf("DELETE /namespaces/mission-a status = %d, want 200", got)
+	}
+	if got := serve(http.MethodGet, "/namespaces/mission-a/tasks", "").Code; got != http.StatusNotFound {
+		t.Errorf("GET tasks of a deleted namespace status = %d, want 404", got)
+	}
+}
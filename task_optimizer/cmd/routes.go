@@ -0,0 +1,138 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strings"
+	"task_optimizer/api"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/buildinfo"
+	"task_optimizer/internal/controller"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+)
+
+// namespacePrefix is the path prefix of the routes of each namespace. The
+// routes of the default namespace are also served without it.
+const namespacePrefix = "/namespaces/{namespace}"
+
+type route struct {
+	pattern string
+	// role is the role that callers need to call the route.
+	role    auth.Role
+	handler http.HandlerFunc
+}
+
+// routes returns the routes of the HTTP API of a namespace, all of them
+// described in the OpenAPI specification. Submitters add and list tasks and
+// templates, operators execute and override tasks and declare outages, and
+// admins manage the satellites, resources and webhooks.
+func routes(taskService *service.TaskService) []route {
+	taskController := controller.NewTaskController(taskService)
+	satelliteController := controller.NewSatelliteController(taskService)
+	taskTemplateController := controller.NewTaskTemplateController(taskService)
+	resourceController := controller.NewResourceController(taskService)
+	outageController := controller.NewOutageController(taskService)
+	eventController := controller.NewEventController(taskService)
+	webhookController := controller.NewWebhookController(taskService)
+
+	return []route{
+		{"GET /tasks", auth.RoleSubmitter, handler.ToHandlerFunc(taskController.ListTasks)},
+		{"POST /tasks", auth.RoleSubmitter, handler.ToHandlerFunc(taskController.AddTasks)},
+		{"GET /tasks/export", auth.RoleSubmitter, taskController.ExportTasks},
+		{"PATCH /tasks/{id}", auth.RoleOperator, handler.ToHandlerFunc(taskController.SetTaskOverrides)},
+		{"POST /tasks/execution", auth.RoleOperator, handler.ToHandlerFunc(taskController.GetHigherProfitTasks)},
+
+		{"GET /events", auth.RoleSubmitter, eventController.StreamEvents},
+
+		{"GET /webhooks", auth.RoleAdmin, handler.ToHandlerFunc(webhookController.ListWebhooks)},
+		{"POST /webhooks", auth.RoleAdmin, handler.ToHandlerFunc(webhookController.AddWebhook)},
+		{"DELETE /webhooks/{id}", auth.RoleAdmin, handler.ToHandlerFunc(webhookController.RemoveWebhook)},
+		{"GET /webhooks/dead-letters", auth.RoleAdmin, handler.ToHandlerFunc(webhookController.ListDeadLetters)},
+
+		{"GET /satellites", auth.RoleSubmitter, handler.ToHandlerFunc(satelliteController.ListSatellites)},
+		{"POST /satellites", auth.RoleAdmin, handler.ToHandlerFunc(satelliteController.AddSatellites)},
+		{"DELETE /satellites/{name}", auth.RoleAdmin, handler.ToHandlerFunc(satelliteController.RemoveSatellite)},
+
+		{"GET /resources", auth.RoleSubmitter, handler.ToHandlerFunc(resourceController.ListResources)},
+		{"POST /resources", auth.RoleAdmin, handler.ToHandlerFunc(resourceController.AddResources)},
+		{"DELETE /resources/{name}", auth.RoleAdmin, handler.ToHandlerFunc(resourceController.RemoveResource)},
+
+		{"GET /outages", auth.RoleSubmitter, handler.ToHandlerFunc(outageController.ListOutages)},
+		{"POST /outages", auth.RoleOperator, handler.ToHandlerFunc(outageController.AddOutage)},
+		{"DELETE /outages/{id}", auth.RoleOperator, handler.ToHandlerFunc(outageController.RemoveOutage)},
+
+		{"GET /task-templates", auth.RoleSubmitter, handler.ToHandlerFunc(taskTemplateController.ListTaskTemplates)},
+		{"POST /task-templates", auth.RoleSubmitter, handler.ToHandlerFunc(taskTemplateController.AddTaskTemplate)},
+		{"GET /task-templates/{id}", auth.RoleSubmitter, handler.ToHandlerFunc(taskTemplateController.GetTaskTemplate)},
+		{"PUT /task-templates/{id}", auth.RoleSubmitter, handler.ToHandlerFunc(taskTemplateController.UpdateTaskTemplate)},
+		{"DELETE /task-templates/{id}", auth.RoleSubmitter, handler.ToHandlerFunc(taskTemplateController.RemoveTaskTemplate)},
+	}
+}
+
+// serviceRoutes returns the routes of the HTTP API outside of the namespaces,
+// all of them described in the OpenAPI specification. Admins create and
+// delete the namespaces, and anyone can probe the health and version of the
+// service.
+func serviceRoutes(namespaces *namespace.Registry, checker *health.Checker) []route {
+	namespaceController := controller.NewNamespaceController(namespaces)
+	healthController := controller.NewHealthController(checker, buildinfo.Get())
+
+	return []route{
+		{"GET /namespaces", auth.RoleSubmitter, handler.ToHandlerFunc(namespaceController.ListNamespaces)},
+		{"POST /namespaces", auth.RoleAdmin, handler.ToHandlerFunc(namespaceController.AddNamespace)},
+		{"DELETE /namespaces/{namespace}", auth.RoleAdmin, handler.ToHandlerFunc(namespaceController.RemoveNamespace)},
+
+		{"GET /healthz", auth.RoleAnonymous, handler.ToHandlerFunc(healthController.Live)},
+		{"GET /readyz", auth.RoleAnonymous, handler.ToHandlerFunc(healthController.Ready)},
+		{"GET /version", auth.RoleAnonymous, handler.ToHandlerFunc(healthController.Version)},
+
+		{"GET /openapi.yaml", auth.RoleAnonymous, serveOpenAPI},
+	}
+}
+
+// newServeMux returns the mux of the routes of the HTTP API and the metrics.
+// The routes of each namespace are served under namespacePrefix, and the
+// ones of the default namespace at the root too. Requests to the routes are
+// logged with their request ID. The readiness of the service is checked with
+// checker. A nil authenticator disables authentication.
+func newServeMux(namespaces *namespace.Registry, checker *health.Checker, authenticator *auth.Authenticator, limiter *handler.Limiter) *http.ServeMux {
+	handle := func(mux *http.ServeMux, prefix string, routes []route) {
+		for _, route := range routes {
+			method, path, _ := strings.Cut(route.pattern, " ")
+			mux.HandleFunc(method+" "+prefix+path, handler.Logged(handler.Authorized(authenticator, route.role, limiter.Limited(route.handler))))
+		}
+	}
+	newNamespaceMux := func(taskService *service.TaskService) http.Handler {
+		mux := http.NewServeMux()
+		handle(mux, namespacePrefix, routes(taskService))
+		return mux
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	handle(mux, "", serviceRoutes(namespaces, checker))
+	defaultNamespace, _ := namespaces.Get(namespace.Default)
+	handle(mux, "", routes(defaultNamespace.Service))
+	mux.HandleFunc(namespacePrefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		requested, err := namespaces.Get(r.PathValue("namespace"))
+		if err != nil {
+			// Unknown namespaces are only reported to authenticated callers.
+			notFound := handler.ToHandlerFunc(func(w http.ResponseWriter, r *http.Request) (int, any) {
+				return http.StatusNotFound, dto.Error{Error: err.Error()}
+			})
+			handler.Logged(handler.Authorized(authenticator, auth.RoleSubmitter, limiter.Limited(notFound)))(w, r)
+			return
+		}
+		requested.Handler(newNamespaceMux).ServeHTTP(w, r)
+	})
+	return mux
+}
+
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(api.OpenAPI)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"gopkg.in/yaml.v3"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"task_optimizer/api"
+	"task_optimizer/client"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+var (
+	taskServiceMetrics = metrics.NewTaskServiceMetricsVec()
+	httpMetrics        = metrics.NewHTTPMetrics()
+)
+
+type openAPISpec struct {
+	Paths      map[string]map[string]any `yaml:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `yaml:"$ref"`
+	Properties map[string]openAPISchema `yaml:"properties"`
+	AllOf      []openAPISchema          `yaml:"allOf"`
+}
+
+func loadOpenAPISpec(t *testing.T) openAPISpec {
+	var spec openAPISpec
+	if err := yaml.Unmarshal(api.OpenAPI, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// properties returns the names of the properties of the schema, including
+// the ones of the schemas it's composed of.
+func (spec openAPISpec) properties(schema openAPISchema) []string {
+	if schema.Ref != "" {
+		schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	var properties []string
+	for name := range schema.Properties {
+		properties = append(properties, name)
+	}
+	for _, part := range schema.AllOf {
+		properties = append(properties, spec.properties(part)...)
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+// jsonFields returns the names of the JSON fields of the struct type,
+// including the ones of its embedded structs.
+func jsonFields(structType reflect.Type) []string {
+	var fields []string
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "-" && field.IsExported() {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestRoutes_OpenAPI(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	// specOperations holds the required role of each operation.
+	specOperations := make(map[string]any)
+	for path, operations := range spec.Paths {
+		for method, operation := range operations {
+			if method == "servers" || method == "parameters" {
+				continue
+			}
+			specOperations[strings.ToUpper(method)+" "+path] = operation.(map[string]any)["x-required-role"]
+		}
+	}
+
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	defaultNamespace, _ := namespaces.Get(namespace.Default)
+	for _, route := range append(routes(defaultNamespace.Service), serviceRoutes(namespaces, health.NewChecker())...) {
+		role, ok := specOperations[route.pattern]
+		if !ok {
+			t.Errorf("route %s is not in the OpenAPI specification", route.pattern)
+		} else if role != route.role.String() {
+			t.Errorf("route %s requires role %s, the OpenAPI specification %v", route.pattern, route.role, role)
+		}
+		delete(specOperations, route.pattern)
+	}
+	for operation := range specOperations {
+		t.Errorf("operation %s of the OpenAPI specification has no route", operation)
+	}
+}
+
+func TestSchemas_OpenAPI(t *testing.T) {
+	spec := loadOpenAPISpec(t)
+	tests := []struct {
+		schema string
+		types  []any
+	}{
+		{"Error", []any{dto.Error{}}},
+		{"Task", []any{dto.Task{}, client.Task{}}},
+		{"TaskPage", []any{dto.TaskPage{}, client.TaskPage{}}},
+		{"Decay", []any{dto.Decay{}, client.Decay{}}},
+		{"ImportReport", []any{dto.ImportReport{}, client.ImportReport{}}},
+		{"ImportError", []any{dto.ImportError{}, client.ImportError{}}},
+		{"TaskOverrides", []any{dto.TaskOverrides{}}},
+		{"Assignment", []any{dto.Assignment{}, client.Assignment{}}},
+		{"ExecutionRequest", []any{dto.ExecutionRequest{}, client.ExecutionRequest{}}},
+		{"Fairness", []any{dto.Fairness{}, client.Fairness{}}},
+		{"Execution", []any{dto.Execution{}, client.Execution{}}},
+		{"ClientShare", []any{dto.ClientShare{}, client.ClientShare{}}},
+		{"Penalty", []any{dto.Penalty{}, client.Penalty{}}},
+		{"Deferral", []any{dto.Deferral{}, client.Deferral{}}},
+		{"Webhook", []any{dto.Webhook{}}},
+		{"DeadLetter", []any{dto.DeadLetter{}}},
+		{"Satellite", []any{dto.Satellite{}}},
+		{"Resource", []any{dto.Resource{}}},
+		{"Outage", []any{dto.Outage{}}},
+		{"TaskTemplate", []any{dto.TaskTemplate{}}},
+		{"Namespace", []any{dto.Namespace{}}},
+		{"Health", []any{dto.Health{}}},
+		{"Readiness", []any{dto.Readiness{}}},
+		{"BuildInfo", []any{dto.BuildInfo{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, ok := spec.Components.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("schema %s is not in the OpenAPI specification", tt.schema)
+			}
+			want := spec.properties(schema)
+			for _, value := range tt.types {
+				if got := jsonFields(reflect.TypeOf(value)); !slices.Equal(got, want) {
+					t.Errorf("%T fields = %v, want %v", value, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	serveOpenAPI(recorder, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	if recorder.Header().Get("Content-Type") != "application/yaml" || !slices.Equal(recorder.Body.Bytes(), api.OpenAPI) {
+		t.Errorf("serveOpenAPI() didn't serve the specification")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// serve serves the HTTP API with httpHandler on httpListener and the gRPC API
+// with grpcServer on grpcListener until a signal arrives on signals or either
+// server fails. Then it stops accepting requests and gives the requests in
+// flight gracePeriod to complete. Requests still running after it are
+// canceled, so that their executions roll back, and serve returns once they
+// are done.
+func serve(httpListener, grpcListener net.Listener, httpHandler http.Handler, grpcServer *grpc.Server, signals <-chan os.Signal, gracePeriod time.Duration) error {
+	requestsCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+	// inFlight tracks the requests being handled. Once draining, new requests
+	// are refused so that none is added while waiting for them.
+	var (
+		inFlightMu sync.Mutex
+		draining   bool
+		inFlight   sync.WaitGroup
+	)
+	httpServer := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlightMu.Lock()
+			if draining {
+				inFlightMu.Unlock()
+				http.Error(w, "shutting down", http.StatusServiceUnavailable)
+				return
+			}
+			inFlight.Add(1)
+			inFlightMu.Unlock()
+			defer inFlight.Done()
+			httpHandler.ServeHTTP(w, r)
+		}),
+		BaseContext: func(net.Listener) context.Context { return requestsCtx },
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- httpServer.Serve(httpListener)
+	}()
+	go func() {
+		errs <- grpcServer.Serve(grpcListener)
+	}()
+	var serveErr error
+	select {
+	case signal := <-signals:
+		log.Info().Str("signal", signal.String()).Dur("grace_period", gracePeriod).Msg("shutting down")
+	case serveErr = <-errs:
+		log.Err(serveErr).Msg("server failed, shutting down")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(grpcStopped)
+	}()
+	if err := httpServer.Shutdown(ctx); err != nil {
+		log.Warn().Msg("grace period over, canceling the HTTP requests in flight")
+		cancelRequests()
+		httpServer.Close()
+	}
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		log.Warn().Msg("grace period over, canceling the gRPC calls in flight")
+		grpcServer.Stop()
+		<-grpcStopped
+	}
+	inFlightMu.Lock()
+	draining = true
+	inFlightMu.Unlock()
+	inFlight.Wait()
+	if errors.Is(serveErr, http.ErrServerClosed) {
+		serveErr = nil
+	}
+	return serveErr
+}
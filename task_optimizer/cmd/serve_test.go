@@ -0,0 +1,102 @@
+package main
+
+import (
+	"google.golang.org/grpc"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestServe_Shutdown(t *testing.T) {
+	tests := []struct {
+		name        string
+		gracePeriod time.Duration
+		// release ends the request in flight within the grace period,
+		// otherwise the grace period runs out.
+		release bool
+	}{
+		{"drained", time.Minute, true},
+		{"canceled", 50 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpListener, grpcListener := listen(t), listen(t)
+			started, released, canceled := make(chan struct{}), make(chan struct{}), make(chan struct{})
+			httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-released:
+					w.WriteHeader(http.StatusOK)
+				case <-r.Context().Done():
+					close(canceled)
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			})
+			signals := make(chan os.Signal, 1)
+			served := make(chan error, 1)
+			go func() {
+				served <- serve(httpListener, grpcListener, httpHandler, grpc.NewServer(), signals, tt.gracePeriod)
+			}()
+
+			responded := make(chan int, 1)
+			go func() {
+				response, err := http.Get("http://" + httpListener.Addr().String())
+				if err != nil {
+					responded <- 0
+					return
+				}
+				response.Body.Close()
+				responded <- response.StatusCode
+			}()
+			<-started
+			signals <- syscall.SIGTERM
+
+			waitRefused(t, httpListener.Addr())
+			waitRefused(t, grpcListener.Addr())
+			if tt.release {
+				close(released)
+				if status := <-responded; status != http.StatusOK {
+					t.Errorf("request in flight status = %d, want %d", status, http.StatusOK)
+				}
+			}
+			select {
+			case err := <-served:
+				if err != nil {
+					t.Errorf("serve() = %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("serve() didn't return")
+			}
+			if !tt.release {
+				select {
+				case <-canceled:
+				default:
+					t.Errorf("request in flight wasn't canceled")
+				}
+			}
+		})
+	}
+}
+
+func listen(t *testing.T) net.Listener {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return listener
+}
+
+// waitRefused waits for the connections to addr to be refused.
+func waitRefused(t *testing.T, addr net.Addr) {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		conn, err := net.Dial("tcp", addr.String())
+		if err != nil {
+			return
+		}
+		conn.Close()
+	}
+	t.Fatalf("connections to %s still accepted", addr)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_InvalidRequest(t *testing.T) {
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	mux := newServeMux(namespaces, health.NewChecker(), nil, handler.NewLimiter(handler.Limits{}, httpMetrics))
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+	}{
+		{"task with unknown priority", "/tasks", `[{"name": "capture", "resources": ["camera"], "profit": 1, "priority": "urgent"}]`},
+		{"execution with negative budget", "/tasks/execution", `{"energyBudget": -1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body)))
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+			}
+			var body dto.Error
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil || body.Error == "" {
+				t.Errorf("body = %q, %v, want an error", recorder.Body, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/handler"
+	"task_optimizer/internal/health"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestServeMux_IfMatch(t *testing.T) {
+	namespaces := namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+	mux := newServeMux(namespaces, health.NewChecker(), nil, handler.NewLimiter(handler.Limits{}, httpMetrics))
+	serve := func(method, path, contentType, ifMatch, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+		request.Header.Set("If-Match", ifMatch)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, request)
+		return recorder
+	}
+	task := `{"name": "capture", "resources": ["camera"], "profit": 1}`
+
+	reviewed := serve(http.MethodGet, "/tasks", "", "", "").Header().Get("ETag")
+	if reviewed == "" {
+		t.Fatal("GET /tasks has no ETag")
+	}
+	added := serve(http.MethodPost, "/tasks", "application/json", reviewed, "["+task+"]")
+	current := added.Header().Get("ETag")
+	if added.Code != http.StatusOK || current == "" || current == reviewed {
+		t.Fatalf("POST /tasks status = %d, ETag = %q, want 200 and a new ETag", added.Code, current)
+	}
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		ifMatch     string
+		body        string
+		wantStatus  int
+	}{
+		{"add at a stale version", http.MethodPost, "/tasks", "application/json", reviewed, "[" + task + "]", http.StatusPreconditionFailed},
+		{"import at a stale version", http.MethodPost, "/tasks", "application/x-ndjson", reviewed, task + "\n", http.StatusPreconditionFailed},
+		{"override at a stale version", http.MethodPatch, "/tasks/1", "application/json", reviewed, `{"pinned": true}`, http.StatusPreconditionFailed},
+		{"execute at a stale version", http.MethodPost, "/tasks/execution", "", reviewed, "", http.StatusPreconditionFailed},
+		{"weak ETag", http.MethodPost, "/tasks/execution", "", "W/" + current, "", http.StatusPreconditionFailed},
+		{"several ETags", http.MethodPost, "/tasks/execution", "", reviewed + ", " + current, "", http.StatusBadRequest},
+		{"execute at the current version", http.MethodPost, "/tasks/execution", "", current, "", http.StatusOK},
+		{"execute at any version", http.MethodPost, "/tasks/execution", "", "*", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serve(tt.method, tt.path, tt.contentType, tt.ifMatch, tt.body).Code; got != tt.wantStatus {
+				t.Errorf("status = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package apierror
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+)
+
+// Code is the class of an error returned to clients, so that every transport
+// reports the same error the same way.
+type Code int
+
+const (
+	Internal Code = iota
+	InvalidArgument
+	NotFound
+	// Forbidden is a request with content the caller is not allowed to
+	// submit, such as pinned tasks from a submitter.
+	Forbidden
+	// Conflict is a request that can't be satisfied in the current state of
+	// the service, such as pinned tasks that conflict.
+	Conflict
+	// TooLarge is a request over the limits of the service.
+	TooLarge
+	// PreconditionFailed is a request made for a version of the task list
+	// that changed since.
+	PreconditionFailed
+	// Canceled is a request canceled before completing, such as an execution
+	// still running when the service shuts down.
+	Canceled
+	// DeadlineExceeded is a request that didn't complete before its
+	// deadline.
+	DeadlineExceeded
+)
+
+// CodeOf returns the class of an error returned by the service, the
+// namespace registry or auth.Require, or by a canceled context.
+func CodeOf(err error) Code {
+	switch {
+	case errors.Is(err, service.ErrUnknownResource),
+		errors.Is(err, service.ErrResourceParentMissing),
+		errors.Is(err, service.ErrInvalidResourceName),
+		errors.Is(err, service.ErrInvalidCursor),
+		errors.Is(err, service.ErrInvalidPriority),
+		errors.Is(err, namespace.ErrInvalidNamespaceName):
+		return InvalidArgument
+	case errors.Is(err, service.ErrTaskNotFound),
+		errors.Is(err, service.ErrResourceNotFound),
+		errors.Is(err, namespace.ErrNamespaceNotFound):
+		return NotFound
+	case errors.Is(err, auth.ErrForbidden):
+		return Forbidden
+	case errors.Is(err, service.ErrPinnedAndHeld),
+		errors.Is(err, service.ErrPinnedConflict),
+		errors.Is(err, service.ErrPinnedNotServed),
+		errors.Is(err, service.ErrPinnedOverBudget),
+		errors.Is(err, service.ErrResourceHasChildren),
+		errors.Is(err, service.ErrResourceInUse),
+		errors.Is(err, namespace.ErrNamespaceExists),
+		errors.Is(err, namespace.ErrDefaultNamespace):
+		return Conflict
+	case errors.Is(err, service.ErrTooManyTasks):
+		return TooLarge
+	case errors.Is(err, service.ErrVersionMismatch):
+		return PreconditionFailed
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Internal
+	}
+}
+
+func (c Code) HTTPStatus() int {
+	switch c {
+	case InvalidArgument:
+		return http.StatusBadRequest
+	case NotFound:
+		return http.StatusNotFound
+	case Forbidden:
+		return http.StatusForbidden
+	case Conflict:
+		return http.StatusConflict
+	case TooLarge:
+		return http.StatusRequestEntityTooLarge
+	case PreconditionFailed:
+		return http.StatusPreconditionFailed
+	case Canceled, DeadlineExceeded:
+		// The request left the service as it was, so it can be retried.
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (c Code) GRPCCode() codes.Code {
+	switch c {
+	case InvalidArgument:
+		return codes.InvalidArgument
+	case NotFound:
+		return codes.NotFound
+	case Forbidden:
+		return codes.PermissionDenied
+	case Conflict:
+		return codes.FailedPrecondition
+	case TooLarge:
+		return codes.ResourceExhausted
+	case PreconditionFailed:
+		return codes.Aborted
+	case Canceled:
+		return codes.Canceled
+	case DeadlineExceeded:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,42 @@
+package apierror
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"net/http"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantHTTP int
+		wantGRPC codes.Code
+	}{
+		{"unknown resource", service.ErrUnknownResource, http.StatusBadRequest, codes.InvalidArgument},
+		{"namespace not found", namespace.ErrNamespaceNotFound, http.StatusNotFound, codes.NotFound},
+		{"forbidden", auth.ErrForbidden, http.StatusForbidden, codes.PermissionDenied},
+		{"pinned conflict", service.ErrPinnedConflict, http.StatusConflict, codes.FailedPrecondition},
+		{"too many tasks", service.ErrTooManyTasks, http.StatusRequestEntityTooLarge, codes.ResourceExhausted},
+		{"version mismatch", service.ErrVersionMismatch, http.StatusPreconditionFailed, codes.Aborted},
+		{"canceled", fmt.Errorf("execution canceled: %w", context.Canceled), http.StatusServiceUnavailable, codes.Canceled},
+		{"deadline exceeded", fmt.Errorf("execution canceled: %w", context.DeadlineExceeded), http.StatusServiceUnavailable, codes.DeadlineExceeded},
+		{"unexpected", fmt.Errorf("unexpected"), http.StatusInternalServerError, codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := CodeOf(tt.err)
+			if got := code.HTTPStatus(); got != tt.wantHTTP {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.wantHTTP)
+			}
+			if got := code.GRPCCode(); got != tt.wantGRPC {
+				t.Errorf("GRPCCode() = %v, want %v", got, tt.wantGRPC)
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Package auth authenticates the callers of the APIs with the API keys of a
+// key file, and authorizes them by the role of their key.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"task_optimizer/internal/ratelimit"
+)
+
+var (
+	ErrMissingCredentials = errors.New("missing API key")
+	ErrInvalidCredentials = errors.New("invalid API key")
+	ErrForbidden          = errors.New("forbidden")
+)
+
+// Role is the set of operations a caller is allowed to call. Each role is
+// allowed the operations of the roles before it.
+type Role int
+
+const (
+	// RoleAnonymous is allowed the operations that need no API key.
+	RoleAnonymous Role = iota + 1
+	// RoleSubmitter adds and lists tasks.
+	RoleSubmitter
+	// RoleOperator executes tasks, overrides them and declares outages.
+	RoleOperator
+	// RoleAdmin manages the namespaces, satellites, resources and webhooks.
+	RoleAdmin
+)
+
+func ParseRole(name string) (Role, error) {
+	switch name {
+	case "submitter":
+		return RoleSubmitter, nil
+	case "operator":
+		return RoleOperator, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return 0, fmt.Errorf("unknown role %q", name)
+	}
+}
+
+func (r Role) String() string {
+	switch r {
+	case RoleAnonymous:
+		return "anonymous"
+	case RoleSubmitter:
+		return "submitter"
+	case RoleOperator:
+		return "operator"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// Identity is the caller of a request.
+type Identity struct {
+	// Name is the name of the API key, empty for anonymous callers.
+	Name string
+	Role Role
+	// RateLimit is the rate limit of the key, nil to apply the default.
+	RateLimit *ratelimit.Limit
+}
+
+// Anonymous is the identity of the callers of operations that need no API
+// key.
+var Anonymous = Identity{Role: RoleAnonymous}
+
+// Unauthenticated is the identity of every caller when authentication is
+// disabled, allowed every operation.
+var Unauthenticated = Identity{Role: RoleAdmin}
+
+// Allows reports whether the identity is allowed the operations of role.
+func (i Identity) Allows(role Role) bool {
+	return i.Role >= role
+}
+
+// Require returns an ErrForbidden error if the caller of the request of ctx
+// is not allowed the operations of role, for requests that need a higher
+// role than their route depending on their content.
+func Require(ctx context.Context, role Role) error {
+	identity := FromContext(ctx)
+	if !identity.Allows(role) {
+		return fmt.Errorf("%w: caller %s has role %s, %s required", ErrForbidden, identity.Name, identity.Role, role)
+	}
+	return nil
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the caller of the request of ctx, or
+// Anonymous if it wasn't authenticated.
+func FromContext(ctx context.Context) Identity {
+	if identity, ok := ctx.Value(identityKey{}).(Identity); ok {
+		return identity
+	}
+	return Anonymous
+}
+
+// KeyFile lists the API keys accepted by the service, in YAML:
+//
+//	keys:
+//	  - name: planning-bot
+//	    role: submitter
+//	    sha256: 8d969eef6ecad3c29a3a629280e686cf0c3f5d5a86aff3ca12020c923adc6c92
+//	  - name: ops-console
+//	    role: operator
+//	    key: a-long-random-key
+//	    rateLimit: 50
+//	    rateBurst: 100
+//
+// Each key is given either in plain text in key or as the hex SHA-256 of the
+// key in sha256, so that the file doesn't have to hold the secrets. Keys with
+// a rateLimit, in requests per second, override the default rate limit.
+type KeyFile struct {
+	Keys []KeyEntry `yaml:"keys"`
+}
+
+type KeyEntry struct {
+	Name   string `yaml:"name"`
+	Role   string `yaml:"role"`
+	Key    string `yaml:"key"`
+	SHA256 string `yaml:"sha256"`
+	// RateLimit and RateBurst override the default rate limit if RateLimit
+	// is not zero.
+	RateLimit float64 `yaml:"rateLimit"`
+	RateBurst int     `yaml:"rateBurst"`
+}
+
+// Authenticator identifies callers by their API key.
+type Authenticator struct {
+	// identities holds the identity of each key by its SHA-256.
+	identities map[[sha256.Size]byte]Identity
+}
+
+// LoadKeyFile reads the key file at path and returns an authenticator of its
+// keys.
+func LoadKeyFile(path string) (*Authenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var keyFile KeyFile
+	if err := yaml.Unmarshal(data, &keyFile); err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	authenticator, err := NewAuthenticator(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", path, err)
+	}
+	return authenticator, nil
+}
+
+func NewAuthenticator(keyFile KeyFile) (*Authenticator, error) {
+	authenticator := &Authenticator{identities: make(map[[sha256.Size]byte]Identity, len(keyFile.Keys))}
+	names := make(map[string]bool, len(keyFile.Keys))
+	for i, entry := range keyFile.Keys {
+		if entry.Name == "" {
+			return nil, fmt.Errorf("key %d has no name", i+1)
+		}
+		if names[entry.Name] {
+			return nil, fmt.Errorf("duplicated key name %q", entry.Name)
+		}
+		names[entry.Name] = true
+		role, err := ParseRole(entry.Role)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", entry.Name, err)
+		}
+		var hash [sha256.Size]byte
+		switch {
+		case entry.Key != "" && entry.SHA256 != "":
+			return nil, fmt.Errorf("key %q has both key and sha256", entry.Name)
+		case entry.Key != "":
+			hash = sha256.Sum256([]byte(entry.Key))
+		case entry.SHA256 != "":
+			decoded, err := hex.DecodeString(entry.SHA256)
+			if err != nil || len(decoded) != sha256.Size {
+				return nil, fmt.Errorf("key %q has an invalid sha256", entry.Name)
+			}
+			copy(hash[:], decoded)
+		default:
+			return nil, fmt.Errorf("key %q has neither key nor sha256", entry.Name)
+		}
+		if _, ok := authenticator.identities[hash]; ok {
+			return nil, fmt.Errorf("key %q is duplicated", entry.Name)
+		}
+		identity := Identity{Name: entry.Name, Role: role}
+		switch {
+		case entry.RateLimit < 0 || entry.RateBurst < 0:
+			return nil, fmt.Errorf("key %q has a negative rate limit", entry.Name)
+		case entry.RateLimit > 0:
+			identity.RateLimit = &ratelimit.Limit{Rate: entry.RateLimit, Burst: entry.RateBurst}
+		case entry.RateBurst > 0:
+			return nil, fmt.Errorf("key %q has rateBurst without rateLimit", entry.Name)
+		}
+		authenticator.identities[hash] = identity
+	}
+	return authenticator, nil
+}
+
+// Authenticate returns the identity of the API key. Keys are looked up by
+// their hash so that the time taken doesn't depend on how much of a key
+// matches.
+func (a *Authenticator) Authenticate(key string) (Identity, error) {
+	if key == "" {
+		return Identity{}, ErrMissingCredentials
+	}
+	identity, ok := a.identities[sha256.Sum256([]byte(key))]
+	if !ok {
+		return Identity{}, ErrInvalidCredentials
+	}
+	return identity, nil
+}
+
+// Authorize authenticates the key, if the authenticator is not nil, and
+// checks that its identity is allowed the operations of role. A nil
+// authenticator disables authentication, allowing every call as
+// Unauthenticated.
+func (a *Authenticator) Authorize(key string, role Role) (Identity, error) {
+	if a == nil {
+		return Unauthenticated, nil
+	}
+	if role == RoleAnonymous {
+		if identity, err := a.Authenticate(key); err == nil {
+			return identity, nil
+		}
+		return Anonymous, nil
+	}
+	identity, err := a.Authenticate(key)
+	if err != nil {
+		return Identity{}, err
+	}
+	if !identity.Allows(role) {
+		return identity, fmt.Errorf("%w: key %s has role %s, %s required", ErrForbidden, identity.Name, identity.Role, role)
+	}
+	return identity, nil
+}
+
+// KeyFromAuthorization returns the key of an "Authorization: Bearer <key>"
+// header value, or "" if it's not a bearer token.
+func KeyFromAuthorization(authorization string) string {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []KeyEntry
+	}{
+		{"missing name", []KeyEntry{{Role: "admin", Key: "a"}}},
+		{"duplicated name", []KeyEntry{{Name: "a", Role: "admin", Key: "a"}, {Name: "a", Role: "admin", Key: "b"}}},
+		{"unknown role", []KeyEntry{{Name: "a", Role: "root", Key: "a"}}},
+		{"no key", []KeyEntry{{Name: "a", Role: "admin"}}},
+		{"key and hash", []KeyEntry{{Name: "a", Role: "admin", Key: "a", SHA256: hashOf("a")}}},
+		{"invalid hash", []KeyEntry{{Name: "a", Role: "admin", SHA256: "abc"}}},
+		{"negative rate limit", []KeyEntry{{Name: "a", Role: "admin", Key: "a", RateLimit: -1}}},
+		{"burst without rate limit", []KeyEntry{{Name: "a", Role: "admin", Key: "a", RateBurst: 5}}},
+		{"duplicated key", []KeyEntry{{Name: "a", Role: "admin", Key: "a"}, {Name: "b", Role: "admin", SHA256: hashOf("a")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAuthenticator(KeyFile{Keys: tt.keys}); err == nil {
+				t.Error("NewAuthenticator() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Authorize(t *testing.T) {
+	authenticator, err := NewAuthenticator(KeyFile{Keys: []KeyEntry{
+		{Name: "planner", Role: "submitter", SHA256: hashOf("planner-key")},
+		{Name: "ops", Role: "operator", Key: "ops-key"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		authenticator *Authenticator
+		key           string
+		role          Role
+		wantIdentity  Identity
+		wantErr       error
+	}{
+		{"disabled", nil, "", RoleAdmin, Unauthenticated, nil},
+		{"missing key", authenticator, "", RoleSubmitter, Identity{}, ErrMissingCredentials},
+		{"invalid key", authenticator, "nope", RoleSubmitter, Identity{}, ErrInvalidCredentials},
+		{"hashed key", authenticator, "planner-key", RoleSubmitter, Identity{Name: "planner", Role: RoleSubmitter}, nil},
+		{"lower role", authenticator, "planner-key", RoleOperator, Identity{Name: "planner", Role: RoleSubmitter}, ErrForbidden},
+		{"higher role", authenticator, "ops-key", RoleSubmitter, Identity{Name: "ops", Role: RoleOperator}, nil},
+		{"anonymous operation", authenticator, "", RoleAnonymous, Anonymous, nil},
+		{"anonymous operation with key", authenticator, "ops-key", RoleAnonymous, Identity{Name: "ops", Role: RoleOperator}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := tt.authenticator.Authorize(tt.key, tt.role)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if identity != tt.wantIdentity {
+				t.Errorf("Authorize() = %+v, want %+v", identity, tt.wantIdentity)
+			}
+		})
+	}
+}
+
+func TestKeyFromAuthorization(t *testing.T) {
+	for header, want := range map[string]string{"Bearer abc": "abc", "bearer abc": "abc", "Basic abc": "", "abc": ""} {
+		if got := KeyFromAuthorization(header); got != want {
+			t.Errorf("KeyFromAuthorization(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func hashOf(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
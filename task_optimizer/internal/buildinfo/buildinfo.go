@@ -0,0 +1,48 @@
+// Package buildinfo holds the version of the service. The commit and build
+// time are set at build time with:
+//
+//	go build -ldflags "-X task_optimizer/internal/buildinfo.Commit=$(git rev-parse HEAD) -X task_optimizer/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Unknown is reported for the build settings that weren't set.
+const Unknown = "unknown"
+
+var (
+	Commit    string
+	BuildTime string
+)
+
+type Info struct {
+	Commit    string
+	BuildTime string
+	GoVersion string
+}
+
+// Get returns the version of the service. When the commit or build time
+// weren't set at build time, the revision and commit time recorded by the Go
+// toolchain are reported instead, if any.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = Unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = Unknown
+	}
+	return info
+}
@@ -0,0 +1,324 @@
+// Package config loads the settings of the service. Each setting takes, from
+// lowest to highest precedence, its default value, the value in the YAML
+// config file, the value of its environment variable and the value of its
+// command line flag.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"task_optimizer/internal/service"
+	"time"
+)
+
+// EnvPrefix prefixes the environment variable of every setting, named after
+// its flag, so -rate-limit is read from TASK_OPTIMIZER_RATE_LIMIT.
+const EnvPrefix = "TASK_OPTIMIZER_"
+
+const (
+	// LogStdout writes the logs to the standard output.
+	LogStdout = "stdout"
+	// LogFile writes the logs to Log.File.
+	LogFile = "file"
+
+	// StorageMemory keeps the tasks in memory, they are lost on restart.
+	StorageMemory = "memory"
+)
+
+// Config holds the settings of the service. A config file holds any of them,
+// for example:
+//
+//	httpAddr: :8080
+//	log:
+//	  output: file
+//	  file: /logs/task_optimizer.log
+//	  level: debug
+//	solver:
+//	  algorithm: branch-and-bound
+//	  timeout: 2s
+//	limits:
+//	  rateLimit: 50
+type Config struct {
+	HTTPAddr string `yaml:"httpAddr"`
+	GRPCAddr string `yaml:"grpcAddr"`
+	// APIKeys is the path of the API key file, authentication is disabled if
+	// empty.
+	APIKeys string  `yaml:"apiKeys"`
+	Log     Log     `yaml:"log"`
+	Storage Storage `yaml:"storage"`
+	Solver  Solver  `yaml:"solver"`
+	Limits  Limits  `yaml:"limits"`
+	// GracePeriod is how long the requests in flight have to complete on
+	// shutdown before they are canceled.
+	GracePeriod time.Duration `yaml:"gracePeriod"`
+}
+
+type Log struct {
+	// Output is LogStdout or LogFile.
+	Output string `yaml:"output"`
+	// File is the path of the log file when Output is LogFile. Its directory
+	// is created if missing.
+	File string `yaml:"file"`
+	// Level is the minimum level logged: trace, debug, info, warn, error,
+	// fatal, panic or disabled.
+	Level string `yaml:"level"`
+}
+
+type Storage struct {
+	// Backend is where the tasks are kept, only StorageMemory for now.
+	Backend string `yaml:"backend"`
+}
+
+type Solver struct {
+	Algorithm service.Solver `yaml:"algorithm"`
+	// Timeout is how long a branch and bound search can take, 0 for no
+	// limit.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type Limits struct {
+	// RateLimit is the requests per second of each API key, or client
+	// address without authentication, 0 for no limit.
+	RateLimit float64 `yaml:"rateLimit"`
+	// RateBurst is the number of requests that each API key can make at once.
+	RateBurst int `yaml:"rateBurst"`
+	// MaxBodyBytes is the size of the largest HTTP request body, 0 for no
+	// limit.
+	MaxBodyBytes int64 `yaml:"maxBodyBytes"`
+	// MaxTasks is the number of tasks that a request can add, 0 for no limit.
+	MaxTasks int `yaml:"maxTasks"`
+}
+
+func Default() Config {
+	return Config{
+		HTTPAddr: ":8080",
+		GRPCAddr: ":50051",
+		Log: Log{
+			Output: LogStdout,
+			Level:  zerolog.LevelInfoValue,
+		},
+		Storage: Storage{Backend: StorageMemory},
+		Solver:  Solver{Algorithm: service.SolverAuto},
+		Limits: Limits{
+			RateLimit:    20,
+			RateBurst:    40,
+			MaxBodyBytes: 16 << 20,
+			MaxTasks:     10000,
+		},
+		GracePeriod: 20 * time.Second,
+	}
+}
+
+// setting is a setting that can be given with a flag and an environment
+// variable. field returns a pointer to the setting in a config.
+type setting struct {
+	flag  string
+	usage string
+	field func(config *Config) any
+}
+
+var settings = []setting{
+	{"http-addr", "address of the HTTP server", func(c *Config) any { return &c.HTTPAddr }},
+	{"grpc-addr", "address of the gRPC server", func(c *Config) any { return &c.GRPCAddr }},
+	{"api-keys", "path of the YAML file of API keys, authentication is disabled if empty", func(c *Config) any { return &c.APIKeys }},
+	{"log-output", "where logs are written, stdout or file", func(c *Config) any { return &c.Log.Output }},
+	{"log-file", "path of the log file when the log output is file", func(c *Config) any { return &c.Log.File }},
+	{"log-level", "minimum level logged", func(c *Config) any { return &c.Log.Level }},
+	{"storage-backend", "where tasks are kept, only memory is supported", func(c *Config) any { return &c.Storage.Backend }},
+	{"solver", "algorithm that plans executions, auto or branch-and-bound", func(c *Config) any { return &c.Solver.Algorithm }},
+	{"solver-timeout", "how long a branch and bound search can take, 0 for no limit", func(c *Config) any { return &c.Solver.Timeout }},
+	{"rate-limit", "requests per second of each API key, or client address without authentication, 0 for no limit", func(c *Config) any { return &c.Limits.RateLimit }},
+	{"rate-burst", "requests that each API key can make at once", func(c *Config) any { return &c.Limits.RateBurst }},
+	{"max-body-bytes", "size of the largest HTTP request body, 0 for no limit", func(c *Config) any { return &c.Limits.MaxBodyBytes }},
+	{"max-tasks", "number of tasks that a request can add, 0 for no limit", func(c *Config) any { return &c.Limits.MaxTasks }},
+	{"grace-period", "how long the requests in flight have to complete on shutdown before they are canceled", func(c *Config) any { return &c.GracePeriod }},
+}
+
+func (s setting) env() string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(s.flag, "-", "_"))
+}
+
+// Load returns the config given by the command line arguments args, without
+// the program name, the environment variables looked up with lookupEnv and
+// the config file at the path given by -config or TASK_OPTIMIZER_CONFIG, if
+// any. It fails with flag.ErrHelp if args ask for help, and with every
+// invalid setting otherwise.
+func Load(args []string, lookupEnv func(key string) (string, bool)) (Config, error) {
+	flags := flag.NewFlagSet("task_optimizer", flag.ContinueOnError)
+	configPath := flags.String("config", "", "path of the YAML config file, also given with "+EnvPrefix+"CONFIG")
+	defaults := Default()
+	flagValues := make(map[string]string)
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s, also given with %s", s.usage, s.env())
+		if value := format(s.field(&defaults)); value != "" && value != "0s" {
+			usage += fmt.Sprintf(" (default %s)", value)
+		}
+		flags.Func(s.flag, usage, func(value string) error {
+			// Values are checked now to report them with the usage, but
+			// applied after the file and the environment.
+			scratch := Default()
+			if err := parse(s.field(&scratch), value); err != nil {
+				return err
+			}
+			flagValues[s.flag] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if flags.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected arguments %q", flags.Args())
+	}
+
+	config := defaults
+	if *configPath == "" {
+		*configPath, _ = lookupEnv(EnvPrefix + "CONFIG")
+	}
+	if *configPath != "" {
+		if err := config.loadFile(*configPath); err != nil {
+			return Config{}, err
+		}
+	}
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env()); ok {
+			if err := parse(s.field(&config), value); err != nil {
+				return Config{}, fmt.Errorf("invalid %s: %w", s.env(), err)
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, ok := flagValues[s.flag]; ok {
+			parse(s.field(&config), value)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// loadFile overrides the config with the settings of the config file at
+// path. Unknown settings are rejected, so that typos don't go unnoticed.
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate returns an error joining the errors of every invalid setting.
+func (c Config) Validate() error {
+	var errs []error
+	for _, addr := range []struct{ name, value string }{{"httpAddr", c.HTTPAddr}, {"grpcAddr", c.GRPCAddr}} {
+		if err := validateAddr(addr.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", addr.name, err))
+		}
+	}
+	switch c.Log.Output {
+	case LogStdout:
+	case LogFile:
+		if c.Log.File == "" {
+			errs = append(errs, errors.New("log.file: required when log.output is file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("log.output: must be %s or %s, not %q", LogStdout, LogFile, c.Log.Output))
+	}
+	if _, err := zerolog.ParseLevel(c.Log.Level); err != nil || c.Log.Level == "" {
+		errs = append(errs, fmt.Errorf("log.level: unknown level %q", c.Log.Level))
+	}
+	if c.Storage.Backend != StorageMemory {
+		errs = append(errs, fmt.Errorf("storage.backend: only %s is supported, not %q", StorageMemory, c.Storage.Backend))
+	}
+	if _, err := service.ParseSolver(string(c.Solver.Algorithm)); err != nil {
+		errs = append(errs, fmt.Errorf("solver.algorithm: %w", err))
+	}
+	if c.Solver.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("solver.timeout: must not be negative, not %s", c.Solver.Timeout))
+	}
+	if c.Limits.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("limits.rateLimit: must not be negative, not %g", c.Limits.RateLimit))
+	}
+	if c.Limits.RateLimit > 0 && c.Limits.RateBurst < 1 {
+		errs = append(errs, fmt.Errorf("limits.rateBurst: must be at least 1 with a rate limit, not %d", c.Limits.RateBurst))
+	}
+	if c.Limits.MaxBodyBytes < 0 {
+		errs = append(errs, fmt.Errorf("limits.maxBodyBytes: must not be negative, not %d", c.Limits.MaxBodyBytes))
+	}
+	if c.Limits.MaxTasks < 0 {
+		errs = append(errs, fmt.Errorf("limits.maxTasks: must not be negative, not %d", c.Limits.MaxTasks))
+	}
+	if c.GracePeriod < 0 {
+		errs = append(errs, fmt.Errorf("gracePeriod: must not be negative, not %s", c.GracePeriod))
+	}
+	return errors.Join(errs...)
+}
+
+func validateAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return fmt.Errorf("invalid port %q", port)
+	}
+	return nil
+}
+
+// parse sets the setting pointed by field to value.
+func parse(field any, value string) error {
+	var err error
+	switch field := field.(type) {
+	case *string:
+		*field = value
+	case *service.Solver:
+		*field = service.Solver(value)
+	case *int:
+		*field, err = strconv.Atoi(value)
+	case *int64:
+		*field, err = strconv.ParseInt(value, 10, 64)
+	case *float64:
+		*field, err = strconv.ParseFloat(value, 64)
+	case *time.Duration:
+		*field, err = time.ParseDuration(value)
+	default:
+		panic(fmt.Sprintf("unsupported setting type %T", field))
+	}
+	return err
+}
+
+// format returns the value of the setting pointed by field.
+func format(field any) string {
+	switch field := field.(type) {
+	case *string:
+		return *field
+	case *service.Solver:
+		return string(*field)
+	case *int:
+		return strconv.Itoa(*field)
+	case *int64:
+		return strconv.FormatInt(*field, 10)
+	case *float64:
+		return strconv.FormatFloat(*field, 'g', -1, 64)
+	case *time.Duration:
+		return field.String()
+	default:
+		panic(fmt.Sprintf("unsupported setting type %T", field))
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"task_optimizer/internal/service"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(`
+httpAddr: :9000
+log:
+  output: file
+  file: /var/log/task_optimizer.log
+solver:
+  algorithm: branch-and-bound
+  timeout: 2s
+limits:
+  rateLimit: 5
+  rateBurst: 10
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"TASK_OPTIMIZER_CONFIG":     path,
+		"TASK_OPTIMIZER_RATE_LIMIT": "7",
+		"TASK_OPTIMIZER_LOG_LEVEL":  "debug",
+		"TASK_OPTIMIZER_HTTP_ADDR":  ":9001",
+	}
+
+	got, err := Load([]string{"-http-addr", "localhost:9002", "-max-tasks=0"}, lookupEnv(env))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.HTTPAddr = "localhost:9002"
+	want.Log = Log{Output: LogFile, File: "/var/log/task_optimizer.log", Level: "debug"}
+	want.Solver = Solver{Algorithm: service.SolverBranchAndBound, Timeout: 2 * time.Second}
+	want.Limits.RateLimit, want.Limits.RateBurst, want.Limits.MaxTasks = 7, 10, 0
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	got, err := Load(nil, lookupEnv(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Default(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	unknownPath := filepath.Join(dir, "unknown.yaml")
+	if err := os.WriteFile(unknownPath, []byte("limits:\n  rateLimits: 5\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		env  map[string]string
+		want []string
+	}{
+		{"flag value", []string{"-rate-limit", "fast"}, nil, []string{"rate-limit"}},
+		{"env value", nil, map[string]string{"TASK_OPTIMIZER_SOLVER_TIMEOUT": "5"}, []string{"TASK_OPTIMIZER_SOLVER_TIMEOUT"}},
+		{"missing file", []string{"-config", filepath.Join(dir, "missing.yaml")}, nil, []string{"missing.yaml"}},
+		{"unknown setting", []string{"-config", unknownPath}, nil, []string{"rateLimits"}},
+		{"arguments", []string{"serve"}, nil, []string{"unexpected arguments"}},
+		{
+			"settings",
+			[]string{"-http-addr", "8080", "-grpc-addr", ":99999", "-log-output", "file", "-log-level", "verbose", "-storage-backend", "postgres", "-solver", "simplex", "-max-tasks", "-1"},
+			nil,
+			[]string{"httpAddr", "grpcAddr", "log.file", "log.level", "storage.backend", "solver.algorithm", "limits.maxTasks"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(tt.args, lookupEnv(tt.env))
+			if err == nil {
+				t.Fatal("Load() didn't fail")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	if _, err := Load([]string{"-h"}, lookupEnv(nil)); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("Load() error = %v, want %v", err, flag.ErrHelp)
+	}
+}
+
+func lookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"task_optimizer/internal/apierror"
+	"task_optimizer/internal/dto"
+)
+
+// errorResponse returns the status and body of an error returned by the
+// service, with the status mapped like in every other transport.
+func errorResponse(err error) (int, any) {
+	return apierror.CodeOf(err).HTTPStatus(), dto.Error{Error: err.Error()}
+}
+
+// decodeErrorResponse returns the status and body of an error decoding the
+// body of a request: 413 if the body is over the size limit, else 400.
+func decodeErrorResponse(r *http.Request, err error) (int, any) {
+	requestLogger(r).Err(err).Send()
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge, dto.Error{Error: err.Error()}
+	}
+	return http.StatusBadRequest, nil
+}
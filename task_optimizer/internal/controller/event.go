@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/service"
+	"time"
+)
+
+// keepAliveInterval is how often a comment is sent on idle event streams so
+// that proxies don't close them.
+const keepAliveInterval = 15 * time.Second
+
+type EventController struct {
+	taskService *service.TaskService
+}
+
+func NewEventController(taskService *service.TaskService) *EventController {
+	return &EventController{
+		taskService: taskService,
+	}
+}
+
+// StreamEvents streams the events of the service as Server-Sent Events until
+// the client disconnects, starting with the events published from then on.
+// Clients resume a stream sending the ID of the last event received in the
+// Last-Event-ID header. Streams lagging behind are closed, and clients have
+// to resume them.
+func (controller *EventController) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	logger := requestLogger(r)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	// New streams start from now on, only resumed streams replay the
+	// buffered events.
+	lastEventID := controller.taskService.LastEventID()
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		var err error
+		if lastEventID, err = strconv.ParseUint(header, 10, 64); err != nil {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+	}
+	subscription, missed := controller.taskService.SubscribeEvents(lastEventID)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	for _, event := range missed {
+		if err := writeEvent(w, event); err != nil {
+			logger.Err(err).Send()
+			return
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.Info().Msg("event stream completed")
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				logger.Err(err).Send()
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok && subscription.BusClosed() {
+				logger.Info().Msg("event stream closed, the namespace was deleted")
+				return
+			}
+			if !ok {
+				logger.Warn().Msg("event stream lagging behind, closed")
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				logger.Err(err).Send()
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(dto.EventDataFromModel(event))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
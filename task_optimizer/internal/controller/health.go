@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+	"task_optimizer/internal/buildinfo"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/health"
+)
+
+type HealthController struct {
+	checker *health.Checker
+	build   buildinfo.Info
+}
+
+func NewHealthController(checker *health.Checker, build buildinfo.Info) *HealthController {
+	return &HealthController{
+		checker: checker,
+		build:   build,
+	}
+}
+
+// Live reports that the service is alive: it responds as long as the
+// process can serve requests.
+func (controller *HealthController) Live(w http.ResponseWriter, r *http.Request) (int, any) {
+	return http.StatusOK, dto.Health{Status: dto.StatusOK}
+}
+
+// Ready reports whether the service is ready to serve requests, with 503
+// and the failed checks when it isn't.
+func (controller *HealthController) Ready(w http.ResponseWriter, r *http.Request) (int, any) {
+	results := controller.checker.Check(r.Context())
+	if !health.Ready(results) {
+		return http.StatusServiceUnavailable, dto.ReadinessFromResults(results)
+	}
+	return http.StatusOK, dto.ReadinessFromResults(results)
+}
+
+func (controller *HealthController) Version(w http.ResponseWriter, r *http.Request) (int, any) {
+	return http.StatusOK, dto.BuildInfoFromModel(controller.build)
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	"github.com/rs/zerolog"
+	"net/http"
+	"task_optimizer/internal/logging"
+)
+
+// requestLogger returns the logger of the request, tagged with its ID.
+func requestLogger(r *http.Request) *zerolog.Logger {
+	return logging.FromContext(r.Context())
+}
+
+// logTaskCount adds to the log of the request the number of tasks it added,
+// listed, exported or executed.
+func logTaskCount(r *http.Request, count int) {
+	requestLogger(r).UpdateContext(func(c zerolog.Context) zerolog.Context {
+		return c.Int("task_count", count)
+	})
+}
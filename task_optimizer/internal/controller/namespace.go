@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/namespace"
+)
+
+type NamespaceController struct {
+	namespaces *namespace.Registry
+}
+
+func NewNamespaceController(namespaces *namespace.Registry) *NamespaceController {
+	return &NamespaceController{
+		namespaces: namespaces,
+	}
+}
+
+// AddNamespace creates an empty namespace from a JSON body with its name.
+func (controller *NamespaceController) AddNamespace(w http.ResponseWriter, r *http.Request) (int, any) {
+	var namespaceDto dto.Namespace
+	err := json.NewDecoder(r.Body).Decode(&namespaceDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	created, err := controller.namespaces.Create(namespaceDto.Name)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	return http.StatusCreated, dto.NamespaceFromModel(created)
+}
+
+func (controller *NamespaceController) ListNamespaces(w http.ResponseWriter, r *http.Request) (int, any) {
+	namespaces := controller.namespaces.List()
+	namespacesDto := make([]dto.Namespace, 0, len(namespaces))
+	for _, info := range namespaces {
+		namespacesDto = append(namespacesDto, dto.NamespaceFromModel(info))
+	}
+	return http.StatusOK, namespacesDto
+}
+
+// RemoveNamespace deletes a namespace with every task, resource and webhook
+// in it. The default namespace can't be deleted.
+func (controller *NamespaceController) RemoveNamespace(w http.ResponseWriter, r *http.Request) (int, any) {
+	if err := controller.namespaces.Delete(r.PathValue("namespace")); err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	return http.StatusOK, nil
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/service"
+)
+
+type OutageController struct {
+	taskService *service.TaskService
+}
+
+func NewOutageController(taskService *service.TaskService) *OutageController {
+	return &OutageController{
+		taskService: taskService,
+	}
+}
+
+func (controller *OutageController) AddOutage(w http.ResponseWriter, r *http.Request) (int, any) {
+	var outageDto dto.Outage
+	err := json.NewDecoder(r.Body).Decode(&outageDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	outage, err := controller.taskService.AddOutage(outageDto.ToModel())
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	return http.StatusCreated, dto.OutageFromModel(outage)
+}
+
+func (controller *OutageController) ListOutages(w http.ResponseWriter, r *http.Request) (int, any) {
+	outages := controller.taskService.ListOutages()
+	outagesDto := make([]dto.Outage, 0, len(outages))
+	for _, outage := range outages {
+		outagesDto = append(outagesDto, dto.OutageFromModel(outage))
+	}
+	return http.StatusOK, outagesDto
+}
+
+func (controller *OutageController) RemoveOutage(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	if !controller.taskService.RemoveOutage(id) {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, nil
+}
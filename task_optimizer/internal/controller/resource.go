@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+)
+
+type ResourceController struct {
+	taskService *service.TaskService
+}
+
+func NewResourceController(taskService *service.TaskService) *ResourceController {
+	return &ResourceController{
+		taskService: taskService,
+	}
+}
+
+func (controller *ResourceController) AddResources(w http.ResponseWriter, r *http.Request) (int, any) {
+	var resourcesDto []dto.Resource
+	err := json.NewDecoder(r.Body).Decode(&resourcesDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	resources := make([]model.Resource, 0, len(resourcesDto))
+	for _, resourceDto := range resourcesDto {
+		resources = append(resources, resourceDto.ToModel())
+	}
+	if err := controller.taskService.AddResources(resources); err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	return http.StatusOK, nil
+}
+
+func (controller *ResourceController) ListResources(w http.ResponseWriter, r *http.Request) (int, any) {
+	resources := controller.taskService.ListResources()
+	resourcesDto := make([]dto.Resource, 0, len(resources))
+	for _, resource := range resources {
+		resourcesDto = append(resourcesDto, dto.ResourceFromModel(resource))
+	}
+	return http.StatusOK, resourcesDto
+}
+
+func (controller *ResourceController) RemoveResource(w http.ResponseWriter, r *http.Request) (int, any) {
+	if err := controller.taskService.RemoveResource(r.PathValue("name")); err != nil {
+		return errorResponse(err)
+	}
+	return http.StatusOK, nil
+}
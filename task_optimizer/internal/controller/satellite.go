@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+)
+
+type SatelliteController struct {
+	taskService *service.TaskService
+}
+
+func NewSatelliteController(taskService *service.TaskService) *SatelliteController {
+	return &SatelliteController{
+		taskService: taskService,
+	}
+}
+
+func (controller *SatelliteController) AddSatellites(w http.ResponseWriter, r *http.Request) (int, any) {
+	var satellitesDto []dto.Satellite
+	err := json.NewDecoder(r.Body).Decode(&satellitesDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	satellites := make([]model.Satellite, 0, len(satellitesDto))
+	for _, satelliteDto := range satellitesDto {
+		satellites = append(satellites, satelliteDto.ToModel())
+	}
+	controller.taskService.AddSatellites(satellites)
+	return http.StatusOK, nil
+}
+
+func (controller *SatelliteController) ListSatellites(w http.ResponseWriter, r *http.Request) (int, any) {
+	satellites := controller.taskService.ListSatellites()
+	satellitesDto := make([]dto.Satellite, 0, len(satellites))
+	for _, satellite := range satellites {
+		satellitesDto = append(satellitesDto, dto.SatelliteFromModel(satellite))
+	}
+	return http.StatusOK, satellitesDto
+}
+
+func (controller *SatelliteController) RemoveSatellite(w http.ResponseWriter, r *http.Request) (int, any) {
+	if !controller.taskService.RemoveSatellite(r.PathValue("name")) {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, nil
+}
@@ -2,11 +2,28 @@ package controller
 
 import (
 	"encoding/json"
-	"github.com/rs/zerolog/log"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task_optimizer/internal/auth"
 	"task_optimizer/internal/dto"
 	"task_optimizer/internal/model"
 	"task_optimizer/internal/service"
+	"task_optimizer/internal/taskio"
+	"time"
+)
+
+const (
+	// importBatchSize is the number of tasks of a streaming import added to
+	// the service at once.
+	importBatchSize = 500
+	// maxImportErrors is the number of line errors reported by a streaming
+	// import, the rest are only counted.
+	maxImportErrors = 100
 )
 
 type TaskController struct {
@@ -19,35 +36,325 @@ func NewTaskController(taskService *service.TaskService) *TaskController {
 	}
 }
 
+// AddTasks adds a JSON array of tasks, all of them or none, or imports a
+// stream of NDJSON or CSV tasks, depending on the Content-Type. With an
+// If-Match header, the tasks are only added if the task list is still at
+// its version.
 func (controller *TaskController) AddTasks(w http.ResponseWriter, r *http.Request) (int, any) {
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return ifMatchErrorResponse(err)
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "" && !strings.HasPrefix(contentType, "application/json") {
+		format, err := taskio.ParseMediaType(contentType)
+		if err != nil {
+			requestLogger(r).Err(err).Send()
+			return http.StatusUnsupportedMediaType, dto.Error{Error: err.Error()}
+		}
+		return controller.importTasks(w, r, taskio.NewDecoder(format, r.Body), ifVersion)
+	}
 	var tasksDto []dto.Task
-	err := json.NewDecoder(r.Body).Decode(&tasksDto)
+	err = json.NewDecoder(r.Body).Decode(&tasksDto)
 	if err != nil {
-		log.Err(err).Send()
-		return http.StatusBadRequest, nil
+		return decodeErrorResponse(r, err)
 	}
+	caller := auth.FromContext(r.Context())
 	tasks := make([]model.Task, 0, len(tasksDto))
 	for _, taskDto := range tasksDto {
-		tasks = append(tasks, taskDto.ToModel())
+		task, err := taskDto.ToModel()
+		if err != nil {
+			requestLogger(r).Err(err).Send()
+			return http.StatusBadRequest, dto.Error{Error: err.Error()}
+		}
+		if err := authorizeOverrides(r, task); err != nil {
+			requestLogger(r).Err(err).Send()
+			return errorResponse(err)
+		}
+		task.SubmittedBy = caller.Name
+		tasks = append(tasks, task)
+	}
+	version, err := controller.taskService.AddTasks(tasks, ifVersion)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
 	}
-	controller.taskService.AddTasks(tasks)
+	logTaskCount(r, len(tasks))
+	setETag(w, version)
 	return http.StatusOK, nil
 }
 
+// ExportTasks streams every task of the queue as NDJSON or CSV, chosen by the
+// format query parameter or else by the Accept header, NDJSON by default.
+func (controller *TaskController) ExportTasks(w http.ResponseWriter, r *http.Request) {
+	logger := requestLogger(r)
+	format, err := exportFormat(r)
+	if err != nil {
+		logger.Err(err).Send()
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	tasks, version := controller.taskService.Snapshot()
+	w.Header().Set("Content-Type", format.MediaType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=tasks.%s", exportExtension[format]))
+	setETag(w, version)
+	w.WriteHeader(http.StatusOK)
+	now := time.Now()
+	encoder := taskio.NewEncoder(format, w)
+	for _, task := range tasks {
+		if err = encoder.Encode(dto.TaskFromModel(task, now)); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = encoder.Flush()
+	}
+	if err != nil {
+		logger.Err(err).Msg("export aborted")
+		return
+	}
+	logTaskCount(r, len(tasks))
+}
+
+var exportExtension = map[taskio.Format]string{
+	taskio.FormatNDJSON: "ndjson",
+	taskio.FormatCSV:    "csv",
+}
+
+func exportFormat(r *http.Request) (taskio.Format, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		return taskio.ParseFormat(name)
+	}
+	for _, mediaType := range strings.Split(r.Header.Get("Accept"), ",") {
+		if format, err := taskio.ParseMediaType(strings.TrimSpace(mediaType)); err == nil {
+			return format, nil
+		}
+	}
+	return taskio.FormatNDJSON, nil
+}
+
+// importTasks adds the valid tasks of the stream in batches as it is read,
+// and reports the invalid lines. The first batch is only added if the task
+// list is at ifVersion, and each of the next ones if the list is still at
+// the version left by the previous one, so that the import aborts if the list
+// is changed by someone else.
+func (controller *TaskController) importTasks(w http.ResponseWriter, r *http.Request, decoder taskio.Decoder, ifVersion uint64) (int, any) {
+	caller := auth.FromContext(r.Context())
+	report := dto.ImportReport{Errors: []dto.ImportError{}}
+	defer func() {
+		logTaskCount(r, report.Imported)
+	}()
+	var batch []model.Task
+	var batchLines []int
+	fail := func(line int, err error) {
+		report.Failed++
+		if len(report.Errors) < maxImportErrors {
+			report.Errors = append(report.Errors, dto.ImportError{Line: line, Error: err.Error()})
+		}
+	}
+	// flush adds the batch, failing its lines if it's invalid. It only
+	// returns an error if the task list is not at ifVersion.
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		version, err := controller.taskService.AddTasks(batch, ifVersion)
+		switch {
+		case errors.Is(err, service.ErrVersionMismatch):
+			requestLogger(r).Err(err).Send()
+			return err
+		case err != nil:
+			for _, line := range batchLines {
+				fail(line, err)
+			}
+		default:
+			report.Imported += len(batch)
+			if ifVersion != service.AnyVersion {
+				ifVersion = version
+			}
+			setETag(w, version)
+		}
+		batch, batchLines = batch[:0], batchLines[:0]
+		return nil
+	}
+	// stop ends the import keeping the tasks imported so far.
+	stop := func(status int, err error) (int, any) {
+		return status, dto.Error{Error: fmt.Sprintf("%v, %d tasks imported", err, report.Imported)}
+	}
+	// changed stops the import when the task list changed under it.
+	changed := func(err error) (int, any) {
+		status, _ := errorResponse(err)
+		return stop(status, err)
+	}
+	// abort stops the import adding the tasks read so far.
+	abort := func(status int, err error) (int, any) {
+		if err := flush(); err != nil {
+			return changed(err)
+		}
+		return stop(status, err)
+	}
+	records := 0
+	for {
+		taskDto, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var recordErr *taskio.RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			// The rest of the stream can't be read.
+			status, _ := decodeErrorResponse(r, err)
+			return abort(status, err)
+		}
+		records++
+		if err := controller.taskService.ValidateTaskCount(records); err != nil {
+			requestLogger(r).Err(err).Send()
+			status, _ := errorResponse(err)
+			return abort(status, err)
+		}
+		if recordErr != nil {
+			fail(recordErr.Line, recordErr.Err)
+			continue
+		}
+		line := decoder.Line()
+		task, err := taskDto.ToModel()
+		if err == nil {
+			err = authorizeOverrides(r, task)
+		}
+		if err == nil {
+			err = controller.taskService.ValidateTask(task)
+		}
+		if err != nil {
+			fail(line, err)
+			continue
+		}
+		task.SubmittedBy = caller.Name
+		batch, batchLines = append(batch, task), append(batchLines, line)
+		if len(batch) == importBatchSize {
+			if err := flush(); err != nil {
+				return changed(err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return changed(err)
+	}
+	return http.StatusOK, report
+}
+
+// GetHigherProfitTasks executes the tasks, if the task list is still at the
+// version of the If-Match header when there is one.
 func (controller *TaskController) GetHigherProfitTasks(w http.ResponseWriter, r *http.Request) (int, any) {
-	taskSubset := controller.taskService.GetHigherProfitSubset()
-	tasksDto := make([]dto.Task, 0, len(taskSubset))
-	for _, task := range taskSubset {
-		tasksDto = append(tasksDto, dto.TaskFromModel(task))
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return ifMatchErrorResponse(err)
+	}
+	var requestDto dto.ExecutionRequest
+	err = json.NewDecoder(r.Body).Decode(&requestDto)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return decodeErrorResponse(r, err)
+	}
+	request, err := requestDto.ToModel()
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
 	}
-	return http.StatusOK, tasksDto
+	request.IfVersion = ifVersion
+	plan, err := controller.taskService.GetHigherProfitSubset(r.Context(), request)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	logTaskCount(r, len(plan.Assignments))
+	setETag(w, plan.Version)
+	return http.StatusOK, dto.ExecutionFromModel(plan)
 }
 
+// SetTaskOverrides pins or holds a task, if the task list is still at the
+// version of the If-Match header when there is one.
+func (controller *TaskController) SetTaskOverrides(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	ifVersion, err := ifMatchVersion(r)
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return ifMatchErrorResponse(err)
+	}
+	var overridesDto dto.TaskOverrides
+	err = json.NewDecoder(r.Body).Decode(&overridesDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	task, version, err := controller.taskService.SetTaskOverrides(id, overridesDto.Pinned, overridesDto.Held, ifVersion)
+	if err != nil {
+		return errorResponse(err)
+	}
+	setETag(w, version)
+	return http.StatusOK, dto.TaskFromModel(task, time.Now())
+}
+
+// authorizeOverrides returns an auth.ErrForbidden error if the task is
+// submitted pinned or held by a caller that isn't allowed to override tasks
+// with SetTaskOverrides.
+func authorizeOverrides(r *http.Request, task model.Task) error {
+	if !task.Pinned && !task.Held {
+		return nil
+	}
+	return auth.Require(r.Context(), auth.RoleOperator)
+}
+
+// ListTasks returns a page of the tasks filtered by the resource, name,
+// minProfit, maxProfit, client and status query parameters, sorted by sort
+// and paginated with cursor and limit, with the version of the task list as
+// its ETag.
 func (controller *TaskController) ListTasks(w http.ResponseWriter, r *http.Request) (int, any) {
-	tasks := controller.taskService.ListAllTasks()
-	tasksDto := make([]dto.Task, 0, len(tasks))
-	for _, task := range tasks {
-		tasksDto = append(tasksDto, dto.TaskFromModel(task))
+	queryDto, err := taskQueryFromURL(r.URL.Query())
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	query, err := queryDto.ToModel()
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	page, err := controller.taskService.QueryTasks(query)
+	if err != nil {
+		return errorResponse(err)
+	}
+	logTaskCount(r, len(page.Tasks))
+	setETag(w, page.Version)
+	return http.StatusOK, dto.TaskPageFromModel(page, time.Now())
+}
+
+func taskQueryFromURL(values url.Values) (dto.TaskQuery, error) {
+	query := dto.TaskQuery{
+		Resources: values["resource"],
+		Name:      values.Get("name"),
+		Clients:   values["client"],
+		Statuses:  values["status"],
+		Sort:      values.Get("sort"),
+		Cursor:    values.Get("cursor"),
+	}
+	for name, bound := range map[string]**float64{"minProfit": &query.MinProfit, "maxProfit": &query.MaxProfit} {
+		if !values.Has(name) {
+			continue
+		}
+		value, err := strconv.ParseFloat(values.Get(name), 64)
+		if err != nil {
+			return dto.TaskQuery{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		*bound = &value
+	}
+	if values.Has("limit") {
+		limit, err := strconv.Atoi(values.Get("limit"))
+		if err != nil {
+			return dto.TaskQuery{}, fmt.Errorf("invalid limit: %w", err)
+		}
+		query.Limit = limit
 	}
-	return http.StatusOK, tasksDto
+	return query, nil
 }
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+)
+
+type TaskTemplateController struct {
+	taskService *service.TaskService
+}
+
+func NewTaskTemplateController(taskService *service.TaskService) *TaskTemplateController {
+	return &TaskTemplateController{
+		taskService: taskService,
+	}
+}
+
+func (controller *TaskTemplateController) AddTaskTemplate(w http.ResponseWriter, r *http.Request) (int, any) {
+	template, err := decodeTaskTemplate(r)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	if err := authorizeOverrides(r, template.Task); err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	if err := controller.taskService.ValidateTask(template.Task); err != nil {
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	template = controller.taskService.AddTaskTemplate(template)
+	return http.StatusCreated, dto.TaskTemplateFromModel(template)
+}
+
+func (controller *TaskTemplateController) ListTaskTemplates(w http.ResponseWriter, r *http.Request) (int, any) {
+	templates := controller.taskService.ListTaskTemplates()
+	templatesDto := make([]dto.TaskTemplate, 0, len(templates))
+	for _, template := range templates {
+		templatesDto = append(templatesDto, dto.TaskTemplateFromModel(template))
+	}
+	return http.StatusOK, templatesDto
+}
+
+func (controller *TaskTemplateController) GetTaskTemplate(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	template, ok := controller.taskService.GetTaskTemplate(id)
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, dto.TaskTemplateFromModel(template)
+}
+
+func (controller *TaskTemplateController) UpdateTaskTemplate(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	template, err := decodeTaskTemplate(r)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	if err := authorizeOverrides(r, template.Task); err != nil {
+		requestLogger(r).Err(err).Send()
+		return errorResponse(err)
+	}
+	if err := controller.taskService.ValidateTask(template.Task); err != nil {
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	template, ok := controller.taskService.UpdateTaskTemplate(id, template)
+	if !ok {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, dto.TaskTemplateFromModel(template)
+}
+
+func (controller *TaskTemplateController) RemoveTaskTemplate(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	if !controller.taskService.RemoveTaskTemplate(id) {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, nil
+}
+
+func decodeTaskTemplate(r *http.Request) (model.TaskTemplate, error) {
+	var templateDto dto.TaskTemplate
+	err := json.NewDecoder(r.Body).Decode(&templateDto)
+	if err != nil {
+		return model.TaskTemplate{}, err
+	}
+	template, err := templateDto.ToModel()
+	if err != nil {
+		return model.TaskTemplate{}, err
+	}
+	template.Task.SubmittedBy = auth.FromContext(r.Context()).Name
+	return template, nil
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/service"
+)
+
+// setETag sets the ETag of the response to the version of the task list.
+func setETag(w http.ResponseWriter, version uint64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatUint(version, 10)))
+}
+
+// ifMatchVersion returns the version of the task list required by the
+// If-Match header of the request, service.AnyVersion if it's missing or "*".
+// ETags that are not versions of the list, weak ETags included, can't match
+// and return service.ErrVersionMismatch.
+func ifMatchVersion(r *http.Request) (uint64, error) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return service.AnyVersion, nil
+	}
+	if strings.Contains(ifMatch, ",") {
+		return 0, errors.New("If-Match must hold a single ETag")
+	}
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err != nil {
+		return 0, fmt.Errorf("%w: ETag %s is not a version of the list", service.ErrVersionMismatch, ifMatch)
+	}
+	version, err := strconv.ParseUint(unquoted, 10, 64)
+	if err != nil || version == service.AnyVersion {
+		return 0, fmt.Errorf("%w: ETag %s is not a version of the list", service.ErrVersionMismatch, ifMatch)
+	}
+	return version, nil
+}
+
+// ifMatchErrorResponse returns the status and body of an error of
+// ifMatchVersion: 412 if the ETag can't match, else 400.
+func ifMatchErrorResponse(err error) (int, any) {
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return errorResponse(err)
+	}
+	return http.StatusBadRequest, dto.Error{Error: err.Error()}
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/service"
+)
+
+type WebhookController struct {
+	taskService *service.TaskService
+}
+
+func NewWebhookController(taskService *service.TaskService) *WebhookController {
+	return &WebhookController{
+		taskService: taskService,
+	}
+}
+
+func (controller *WebhookController) AddWebhook(w http.ResponseWriter, r *http.Request) (int, any) {
+	var webhookDto dto.Webhook
+	err := json.NewDecoder(r.Body).Decode(&webhookDto)
+	if err != nil {
+		return decodeErrorResponse(r, err)
+	}
+	webhook, err := controller.taskService.AddWebhook(webhookDto.ToModel())
+	if err != nil {
+		requestLogger(r).Err(err).Send()
+		return http.StatusBadRequest, dto.Error{Error: err.Error()}
+	}
+	return http.StatusCreated, dto.WebhookFromModel(webhook)
+}
+
+func (controller *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) (int, any) {
+	webhooks := controller.taskService.ListWebhooks()
+	webhooksDto := make([]dto.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhooksDto = append(webhooksDto, dto.WebhookFromModel(webhook))
+	}
+	return http.StatusOK, webhooksDto
+}
+
+func (controller *WebhookController) RemoveWebhook(w http.ResponseWriter, r *http.Request) (int, any) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return http.StatusBadRequest, nil
+	}
+	if !controller.taskService.RemoveWebhook(id) {
+		return http.StatusNotFound, nil
+	}
+	return http.StatusOK, nil
+}
+
+func (controller *WebhookController) ListDeadLetters(w http.ResponseWriter, r *http.Request) (int, any) {
+	deadLetters := controller.taskService.ListDeadLetters()
+	deadLettersDto := make([]dto.DeadLetter, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		deadLettersDto = append(deadLettersDto, dto.DeadLetterFromModel(deadLetter))
+	}
+	return http.StatusOK, deadLettersDto
+}
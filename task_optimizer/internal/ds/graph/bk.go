@@ -1,8 +1,14 @@
 package graph
 
-import "task_optimizer/internal/ds/set"
+import (
+	"context"
+	"task_optimizer/internal/ds/set"
+)
 
-func BronKerbosch(r, p, x set.Set[int], graph Graph) (set.Set[int], float64) {
+// BronKerbosch returns the maximal clique of maximum weight that extends r
+// with nodes of p and none of x, and its weight. Once ctx is done the search
+// stops and returns the best clique found so far.
+func BronKerbosch(ctx context.Context, r, p, x set.Set[int], graph Graph) (set.Set[int], float64) {
 	if len(p) == 0 && len(x) == 0 {
 		var weight float64
 		for node := range r {
@@ -13,13 +19,13 @@ func BronKerbosch(r, p, x set.Set[int], graph Graph) (set.Set[int], float64) {
 
 	var maximalWeightClique set.Set[int]
 	var maximalWeight float64
-	for len(p) > 0 {
+	for len(p) > 0 && ctx.Err() == nil {
 		v := p.Pop()
 		vNeighbors := graph.GetNeighbors(v)
 		rv := r.Clone().Add(v)
 		pv := p.Clone().Intersect(vNeighbors)
 		xv := x.Clone().Intersect(vNeighbors)
-		clique, weight := BronKerbosch(rv, pv, xv, graph)
+		clique, weight := BronKerbosch(ctx, rv, pv, xv, graph)
 		x.Add(v)
 		if weight > maximalWeight {
 			maximalWeightClique = clique
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"reflect"
 	"task_optimizer/internal/ds/set"
 	"testing"
@@ -109,6 +110,7 @@ func TestBronKerbosch(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cliqueNodes, weight := BronKerbosch(
+				context.Background(),
 				set.Empty[int](),
 				tt.graph.GetNodes(),
 				set.Empty[int](),
@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"math"
+	"task_optimizer/internal/ds/set"
+	"time"
+)
+
+// Objective scores the cliques explored by MaxScoreClique.
+type Objective interface {
+	// Score returns the score of the clique and whether the clique satisfies
+	// the constraints of the objective.
+	Score(clique set.Set[int]) (float64, bool)
+	// Bound returns an upper bound of the score of every clique that
+	// contains clique and is contained in clique ∪ candidates.
+	Bound(clique, candidates set.Set[int]) float64
+	// Extendable reports whether the clique or any clique containing it may
+	// satisfy the constraints. Cliques that aren't extendable are pruned.
+	Extendable(clique set.Set[int]) bool
+}
+
+// MaxScoreClique returns the clique with maximum score among the cliques
+// that extend r with nodes of p and satisfy the constraints of the
+// objective, and whether such a clique exists. Non maximal cliques are also
+// considered, branches are pruned using the bound of the objective.
+func MaxScoreClique(r, p set.Set[int], graph Graph, objective Objective) (set.Set[int], float64, bool) {
+	if !objective.Extendable(r) {
+		return set.Empty[int](), math.Inf(-1), false
+	}
+	maximalScoreClique, maximalScore, found := set.Empty[int](), math.Inf(-1), false
+	if score, ok := objective.Score(r); ok {
+		maximalScoreClique, maximalScore, found = r, score, true
+	}
+
+	for len(p) > 0 {
+		if found && objective.Bound(r, p) <= maximalScore {
+			break
+		}
+		v := p.Pop()
+		rv := r.Clone().Add(v)
+		if !objective.Extendable(rv) {
+			continue
+		}
+		pv := p.Clone().Intersect(graph.GetNeighbors(v))
+		clique, score, ok := MaxScoreClique(rv, pv, graph, objective)
+		if ok && (!found || score > maximalScore) {
+			maximalScoreClique, maximalScore, found = clique, score, true
+		}
+	}
+
+	return maximalScoreClique, maximalScore, found
+}
+
+// MaxScoreCliqueCovering returns the clique with maximum score like
+// MaxScoreClique, among the cliques that also contain a node of each of the
+// groups. The search picks the node of each group in turn before the rest of
+// the nodes, so branches that leave a group out are never explored and the
+// picks are pruned with the bound of the objective like any other branch.
+func MaxScoreCliqueCovering(r, p set.Set[int], groups []set.Set[int], graph Graph, objective Objective) (set.Set[int], float64, bool) {
+	if len(groups) == 0 {
+		return MaxScoreClique(r, p, graph, objective)
+	}
+	maximalScoreClique, maximalScore, found := set.Empty[int](), math.Inf(-1), false
+	if !objective.Extendable(r) {
+		return maximalScoreClique, maximalScore, found
+	}
+
+	picks := groups[0].Intersect(p)
+	for len(picks) > 0 {
+		if found && objective.Bound(r, p) <= maximalScore {
+			break
+		}
+		v := picks.Pop()
+		// The next picks don't take v, so the cliques containing it are only
+		// explored in this branch.
+		p.Remove(v)
+		rv := r.Clone().Add(v)
+		if !objective.Extendable(rv) {
+			continue
+		}
+		pv := p.Intersect(graph.GetNeighbors(v))
+		clique, score, ok := MaxScoreCliqueCovering(rv, pv, groups[1:], graph, objective)
+		if ok && (!found || score > maximalScore) {
+			maximalScoreClique, maximalScore, found = clique, score, true
+		}
+	}
+
+	return maximalScoreClique, maximalScore, found
+}
+
+// DeadlineObjective bounds the time MaxScoreClique takes with Objective.
+// Once Deadline has passed the search turns greedy: it extends the clique it
+// is at with the first candidate that keeps it extendable, and so on until
+// there is none, and prunes every other branch, returning the best clique
+// found so far.
+type DeadlineObjective struct {
+	Objective
+	Deadline time.Time
+	// Expired is set when the deadline pruned a branch.
+	Expired bool
+
+	// depth is the size of the cliques extending the deepest clique bounded
+	// after the deadline, and rejected whether the last one wasn't
+	// extendable.
+	depth    int
+	rejected bool
+}
+
+func (o *DeadlineObjective) Bound(clique, candidates set.Set[int]) float64 {
+	if !o.Expired && !time.Now().After(o.Deadline) {
+		return o.Objective.Bound(clique, candidates)
+	}
+	o.Expired = true
+	if len(clique) >= o.depth || (len(clique)+1 == o.depth && o.rejected) {
+		o.depth, o.rejected = len(clique)+1, false
+		return math.Inf(1)
+	}
+	return math.Inf(-1)
+}
+
+func (o *DeadlineObjective) Extendable(clique set.Set[int]) bool {
+	extendable := o.Objective.Extendable(clique)
+	if o.Expired && !extendable && len(clique) == o.depth {
+		o.rejected = true
+	}
+	return extendable
+}
+
+// CliquePenalty returns the sum of the penalties of every pair of nodes of
+// the clique.
+func CliquePenalty(clique set.Set[int], graph PenaltyGraph) float64 {
+	nodes := clique.Slice()
+	var penalty float64
+	for i, node := range nodes {
+		for _, other := range nodes[i+1:] {
+			penalty += graph.GetPenalty(node, other)
+		}
+	}
+	return penalty
+}
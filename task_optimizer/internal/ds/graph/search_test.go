@@ -0,0 +1,277 @@
+package graph
+
+import (
+	"reflect"
+	"task_optimizer/internal/ds/set"
+	"testing"
+	"time"
+)
+
+// sizeObjective scores cliques by their weight, only accepts cliques with
+// exactly size nodes and prunes cliques with more than size nodes.
+type sizeObjective struct {
+	graph Graph
+	size  int
+}
+
+func (o sizeObjective) Score(clique set.Set[int]) (float64, bool) {
+	var weight float64
+	for node := range clique {
+		weight += o.graph.GetWeight(node)
+	}
+	return weight, len(clique) == o.size
+}
+
+func (o sizeObjective) Bound(clique, candidates set.Set[int]) float64 {
+	bound, _ := o.Score(clique)
+	for node := range candidates {
+		bound += o.graph.GetWeight(node)
+	}
+	return bound
+}
+
+func (o sizeObjective) Extendable(clique set.Set[int]) bool {
+	return len(clique) <= o.size
+}
+
+func TestMaxScoreClique(t *testing.T) {
+	k4 := GraphImpl{
+		weights: []float64{1, 2, 3, 4},
+		adjacency: [][]bool{
+			{false, true, true, true},
+			{true, false, true, true},
+			{true, true, false, true},
+			{true, true, true, false},
+		},
+	}
+	c4 := GraphImpl{
+		weights: []float64{1, 2, 3, 4},
+		adjacency: [][]bool{
+			{false, true, false, true},
+			{true, false, true, false},
+			{false, true, false, true},
+			{true, false, true, false},
+		},
+	}
+	tests := []struct {
+		name       string
+		graph      Graph
+		seed       set.Set[int]
+		size       int
+		wantNodes  set.Set[int]
+		wantScore  float64
+		wantExists bool
+	}{
+		{
+			name:       "K₄, clique of size 2",
+			graph:      k4,
+			seed:       set.Empty[int](),
+			size:       2,
+			wantNodes:  set.Of(2, 3),
+			wantScore:  7,
+			wantExists: true,
+		},
+		{
+			name:       "K₄, clique of size 3 with a seed",
+			graph:      k4,
+			seed:       set.Of(0),
+			size:       3,
+			wantNodes:  set.Of(0, 2, 3),
+			wantScore:  8,
+			wantExists: true,
+		},
+		{
+			name:       "C₄, clique of size 3 doesn't exist",
+			graph:      c4,
+			seed:       set.Empty[int](),
+			size:       3,
+			wantNodes:  set.Empty[int](),
+			wantExists: false,
+		},
+		{
+			name:       "C₄, seed not extendable",
+			graph:      c4,
+			seed:       set.Of(0, 1),
+			size:       1,
+			wantNodes:  set.Empty[int](),
+			wantExists: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.graph.GetNodes().Difference(tt.seed)
+			for node := range tt.seed {
+				p = p.Intersect(tt.graph.GetNeighbors(node))
+			}
+			cliqueNodes, score, exists := MaxScoreClique(tt.seed, p, tt.graph, sizeObjective{tt.graph, tt.size})
+			if exists != tt.wantExists {
+				t.Fatalf("MaxScoreClique() got exists = %v, want %v", exists, tt.wantExists)
+			}
+			if !reflect.DeepEqual(cliqueNodes, tt.wantNodes) {
+				t.Errorf("MaxScoreClique() got nodes = %v, want %v", cliqueNodes, tt.wantNodes)
+			}
+			if exists && score != tt.wantScore {
+				t.Errorf("MaxScoreClique() got score = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestMaxScoreCliqueCovering(t *testing.T) {
+	k4 := GraphImpl{
+		weights: []float64{1, 2, 3, 4},
+		adjacency: [][]bool{
+			{false, true, true, true},
+			{true, false, true, true},
+			{true, true, false, true},
+			{true, true, true, false},
+		},
+	}
+	c4 := GraphImpl{
+		weights: []float64{1, 2, 3, 4},
+		adjacency: [][]bool{
+			{false, true, false, true},
+			{true, false, true, false},
+			{false, true, false, true},
+			{true, false, true, false},
+		},
+	}
+	tests := []struct {
+		name       string
+		graph      Graph
+		groups     []set.Set[int]
+		size       int
+		wantNodes  set.Set[int]
+		wantScore  float64
+		wantExists bool
+	}{
+		{
+			name:       "K₄, no groups",
+			graph:      k4,
+			size:       2,
+			wantNodes:  set.Of(2, 3),
+			wantScore:  7,
+			wantExists: true,
+		},
+		{
+			name:       "K₄, one of two nodes",
+			graph:      k4,
+			groups:     []set.Set[int]{set.Of(0, 1)},
+			size:       3,
+			wantNodes:  set.Of(1, 2, 3),
+			wantScore:  9,
+			wantExists: true,
+		},
+		{
+			name:       "C₄, one node",
+			graph:      c4,
+			groups:     []set.Set[int]{set.Of(0)},
+			size:       2,
+			wantNodes:  set.Of(0, 3),
+			wantScore:  5,
+			wantExists: true,
+		},
+		{
+			name:       "C₄, a node of each group",
+			graph:      c4,
+			groups:     []set.Set[int]{set.Of(0), set.Of(1, 2)},
+			size:       2,
+			wantNodes:  set.Of(0, 1),
+			wantScore:  3,
+			wantExists: true,
+		},
+		{
+			name:       "C₄, groups not adjacent",
+			graph:      c4,
+			groups:     []set.Set[int]{set.Of(0), set.Of(2)},
+			size:       2,
+			wantNodes:  set.Empty[int](),
+			wantExists: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cliqueNodes, score, exists := MaxScoreCliqueCovering(set.Empty[int](), tt.graph.GetNodes(), tt.groups, tt.graph, sizeObjective{tt.graph, tt.size})
+			if exists != tt.wantExists {
+				t.Fatalf("MaxScoreCliqueCovering() got exists = %v, want %v", exists, tt.wantExists)
+			}
+			if !reflect.DeepEqual(cliqueNodes, tt.wantNodes) {
+				t.Errorf("MaxScoreCliqueCovering() got nodes = %v, want %v", cliqueNodes, tt.wantNodes)
+			}
+			if exists && score != tt.wantScore {
+				t.Errorf("MaxScoreCliqueCovering() got score = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+type PenaltyGraphImpl struct {
+	GraphImpl
+	penalties [][]float64
+}
+
+func (g PenaltyGraphImpl) GetPenalty(node, other int) float64 {
+	return g.penalties[node][other]
+}
+
+func TestMaxScoreClique_Deadline(t *testing.T) {
+	k4 := GraphImpl{
+		weights: []float64{1, 2, 3, 4},
+		adjacency: [][]bool{
+			{false, true, true, true},
+			{true, false, true, true},
+			{true, true, false, true},
+			{true, true, true, false},
+		},
+	}
+	objective := &DeadlineObjective{Objective: sizeObjective{graph: k4, size: 2}, Deadline: time.Now()}
+	clique, _, found := MaxScoreClique(set.Empty[int](), k4.GetNodes(), k4, objective)
+	if !found || len(clique) != 2 {
+		t.Errorf("MaxScoreClique() after the deadline = %v, %v, want a clique of 2 nodes", clique, found)
+	}
+	if !objective.Expired {
+		t.Errorf("Expired = false, want true")
+	}
+
+	objective = &DeadlineObjective{Objective: sizeObjective{graph: k4, size: 2}, Deadline: time.Now().Add(time.Hour)}
+	clique, score, _ := MaxScoreClique(set.Empty[int](), k4.GetNodes(), k4, objective)
+	if !reflect.DeepEqual(clique, set.Of(2, 3)) || score != 7 || objective.Expired {
+		t.Errorf("MaxScoreClique() before the deadline = %v, %v, expired %v, want [2 3], 7", clique, score, objective.Expired)
+	}
+}
+
+func TestCliquePenalty(t *testing.T) {
+	graph := PenaltyGraphImpl{
+		GraphImpl: GraphImpl{
+			weights: []float64{1, 2, 3},
+			adjacency: [][]bool{
+				{false, true, true},
+				{true, false, true},
+				{true, true, false},
+			},
+		},
+		penalties: [][]float64{
+			{0, 0.5, 0},
+			{0.5, 0, 1},
+			{0, 1, 0},
+		},
+	}
+	tests := []struct {
+		name   string
+		clique set.Set[int]
+		want   float64
+	}{
+		{"empty clique", set.Empty[int](), 0},
+		{"single node", set.Of(1), 0},
+		{"pair without penalty", set.Of(0, 2), 0},
+		{"pair with penalty", set.Of(0, 1), 0.5},
+		{"every node", set.Of(0, 1, 2), 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CliquePenalty(tt.clique, graph); got != tt.want {
+				t.Errorf("CliquePenalty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,3 +7,10 @@ type Graph interface {
 	GetNeighbors(node int) set.Set[int]
 	GetWeight(node int) float64
 }
+
+// PenaltyGraph is a Graph where including two adjacent nodes in a clique
+// has a penalty.
+type PenaltyGraph interface {
+	Graph
+	GetPenalty(node, other int) float64
+}
@@ -1,6 +1,7 @@
 package taskgraph
 
 import (
+	"sort"
 	"task_optimizer/internal/ds/set"
 	"task_optimizer/internal/model"
 )
@@ -8,6 +9,20 @@ import (
 type TaskCompatibilityGraph struct {
 	tasks            []model.Task
 	compatibilityMap map[int]set.Set[int]
+
+	// satellites and origins are only set for fleet graphs, where each node
+	// is a task assigned to satellites[node] and origins[node] is the index
+	// of the task in the input list.
+	satellites []string
+	origins    []int
+
+	// penalties holds the penalty paid for each pair of compatible nodes
+	// sharing resources, nil if no pair does.
+	penalties map[int]map[int]float64
+
+	// weights holds the weight of each node when set with WithWeights,
+	// otherwise the weight of a node is the profit of its task.
+	weights []float64
 }
 
 func (t TaskCompatibilityGraph) GetNodes() set.Set[int] {
@@ -24,15 +39,84 @@ func (t TaskCompatibilityGraph) GetNeighbors(node int) set.Set[int] {
 }
 
 func (t TaskCompatibilityGraph) GetWeight(node int) float64 {
+	if node < 0 || node >= len(t.tasks) {
+		return 0
+	}
+	if t.weights != nil {
+		return t.weights[node]
+	}
+	return t.tasks[node].Profit
+}
+
+// WithWeights returns a copy of the graph where the weight of each node is
+// the result of applying weight to its task.
+func (t TaskCompatibilityGraph) WithWeights(weight func(task model.Task) float64) TaskCompatibilityGraph {
+	t.weights = make([]float64, len(t.tasks))
+	for node, task := range t.tasks {
+		t.weights[node] = weight(task)
+	}
+	return t
+}
+
+// GetPenalty returns the penalty paid for including both nodes in a clique.
+func (t TaskCompatibilityGraph) GetPenalty(node, other int) float64 {
+	return t.penalties[node][other]
+}
+
+// PenaltyTotal returns the sum of the penalties of every pair of nodes, the
+// most that any clique can pay.
+func (t TaskCompatibilityGraph) PenaltyTotal() float64 {
+	var total float64
+	for node, penalties := range t.penalties {
+		for other, penalty := range penalties {
+			if node < other {
+				total += penalty
+			}
+		}
+	}
+	return total
+}
+
+func (t TaskCompatibilityGraph) HasPenalties() bool {
+	return t.penalties != nil
+}
+
+func (t *TaskCompatibilityGraph) addEdge(node, other int, penalty float64) {
+	t.compatibilityMap[node].Add(other)
+	t.compatibilityMap[other].Add(node)
+	if penalty <= 0 || node == other {
+		return
+	}
+	if t.penalties == nil {
+		t.penalties = make(map[int]map[int]float64)
+	}
+	for _, pair := range [][2]int{{node, other}, {other, node}} {
+		if t.penalties[pair[0]] == nil {
+			t.penalties[pair[0]] = make(map[int]float64)
+		}
+		t.penalties[pair[0]][pair[1]] = penalty
+	}
+}
+
+func (t TaskCompatibilityGraph) GetTask(node int) model.Task {
 	if node >= 0 && node < len(t.tasks) {
-		return t.tasks[node].Profit
+		return t.tasks[node]
+	}
+	return model.Task{}
+}
+
+func (t TaskCompatibilityGraph) GetCost(node int) float64 {
+	if node >= 0 && node < len(t.tasks) {
+		return t.tasks[node].Energy
 	}
 	return 0
 }
 
+// GetTasksFromNodes returns the tasks of the nodes, in the order of the
+// nodes.
 func (t TaskCompatibilityGraph) GetTasksFromNodes(nodes set.Set[int]) []model.Task {
 	tasks := make([]model.Task, 0, len(nodes))
-	for node := range nodes {
+	for _, node := range sortedNodes(nodes) {
 		if node >= 0 && node < len(t.tasks) {
 			tasks = append(tasks, t.tasks[node])
 		}
@@ -41,7 +125,52 @@ func (t TaskCompatibilityGraph) GetTasksFromNodes(nodes set.Set[int]) []model.Ta
 	return tasks
 }
 
-func BuildCompatibilityGraph(tasks []model.Task) TaskCompatibilityGraph {
+// GetAssignmentsFromNodes returns the assignments of the nodes, in the order
+// of the nodes.
+func (t TaskCompatibilityGraph) GetAssignmentsFromNodes(nodes set.Set[int]) []model.Assignment {
+	assignments := make([]model.Assignment, 0, len(nodes))
+	for _, node := range sortedNodes(nodes) {
+		if node < 0 || node >= len(t.tasks) {
+			continue
+		}
+		assignment := model.Assignment{Task: t.tasks[node]}
+		if t.satellites != nil {
+			assignment.Satellite = t.satellites[node]
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	return assignments
+}
+
+// GetTaskIndexesFromNodes returns the indexes in the input task list of the
+// tasks represented by nodes.
+func (t TaskCompatibilityGraph) GetTaskIndexesFromNodes(nodes set.Set[int]) set.Set[int] {
+	indexes := make(set.Set[int], len(nodes))
+	for node := range nodes {
+		if node < 0 || node >= len(t.tasks) {
+			continue
+		}
+		if t.origins != nil {
+			indexes.Add(t.origins[node])
+		} else {
+			indexes.Add(node)
+		}
+	}
+
+	return indexes
+}
+
+func sortedNodes(nodes set.Set[int]) []int {
+	sorted := nodes.Slice()
+	sort.Ints(sorted)
+	return sorted
+}
+
+// BuildCompatibilityGraph builds a graph where each node is a task and two
+// nodes are compatible if the tasks can be executed together under the
+// resource policy, paying the penalty of the resources they share.
+func BuildCompatibilityGraph(tasks []model.Task, policy model.ResourcePolicy) TaskCompatibilityGraph {
 	cGraph := TaskCompatibilityGraph{
 		tasks:            tasks[:],
 		compatibilityMap: make(map[int]set.Set[int], len(tasks)),
@@ -52,9 +181,47 @@ func BuildCompatibilityGraph(tasks []model.Task) TaskCompatibilityGraph {
 	for i, task := range tasks {
 		for j := i; j < len(tasks); j++ {
 			otherTask := tasks[j]
-			if task.IsCompatible(otherTask) {
-				cGraph.compatibilityMap[i].Add(j)
-				cGraph.compatibilityMap[j].Add(i)
+			if compatible, penalty := task.Conflict(otherTask, policy); compatible {
+				cGraph.addEdge(i, j, penalty)
+			}
+		}
+	}
+
+	return cGraph
+}
+
+// BuildFleetCompatibilityGraph builds a graph where each node is a task
+// assigned to one of the satellites that can serve it. Two nodes are
+// compatible if they are different tasks and either run on different
+// satellites or can be executed together under the resource policy. Nodes
+// for the same task are never compatible, so a clique assigns each task to
+// at most one satellite.
+func BuildFleetCompatibilityGraph(tasks []model.Task, satellites []model.Satellite, policy model.ResourcePolicy) TaskCompatibilityGraph {
+	cGraph := TaskCompatibilityGraph{
+		tasks:            []model.Task{},
+		compatibilityMap: map[int]set.Set[int]{},
+		satellites:       []string{},
+		origins:          []int{},
+	}
+	for i, task := range tasks {
+		for _, satellite := range satellites {
+			if satellite.CanServe(task) {
+				cGraph.compatibilityMap[len(cGraph.tasks)] = set.Set[int]{}
+				cGraph.tasks = append(cGraph.tasks, task)
+				cGraph.satellites = append(cGraph.satellites, satellite.Name)
+				cGraph.origins = append(cGraph.origins, i)
+			}
+		}
+	}
+	for i, task := range cGraph.tasks {
+		for j := i + 1; j < len(cGraph.tasks); j++ {
+			if cGraph.origins[i] == cGraph.origins[j] {
+				continue
+			}
+			if cGraph.satellites[i] != cGraph.satellites[j] {
+				cGraph.addEdge(i, j, 0)
+			} else if compatible, penalty := task.Conflict(cGraph.tasks[j], policy); compatible {
+				cGraph.addEdge(i, j, penalty)
 			}
 		}
 	}
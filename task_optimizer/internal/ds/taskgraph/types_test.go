@@ -24,11 +24,11 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 		{
 			name: "list with one task",
 			tasks: []model.Task{
-				{"task1", set.Of[string]("resource"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
 			},
 			want: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -38,13 +38,13 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 		{
 			name: "list with two compatible tasks",
 			tasks: []model.Task{
-				{"task1", set.Of[string]("resource1"), 1.2},
-				{"task2", set.Of[string]("resource2"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
 			},
 			want: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource2"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Of[int](1),
@@ -55,13 +55,13 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 		{
 			name: "list with two incompatible tasks",
 			tasks: []model.Task{
-				{"task1", set.Of[string]("resource"), 1.2},
-				{"task2", set.Of[string]("resource"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
 			},
 			want: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource"), 1.2},
-					{"task2", set.Of[string]("resource"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -72,15 +72,15 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 		{
 			name: "list with two compatible tasks and one incompatible",
 			tasks: []model.Task{
-				{"task1", set.Of[string]("resource1"), 1.2},
-				{"task2", set.Of[string]("resource2"), 1.2},
-				{"task3", set.Of[string]("resource1"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
+				{Name: "task3", Resources: set.Of[string]("resource1"), Profit: 1.2},
 			},
 			want: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource2"), 1.2},
-					{"task3", set.Of[string]("resource1"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
+					{Name: "task3", Resources: set.Of[string]("resource1"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Of[int](1),
@@ -92,13 +92,13 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 		{
 			name: "list with two compatible tasks with two resources",
 			tasks: []model.Task{
-				{"task1", set.Of[string]("resource1", "resource2"), 1.2},
-				{"task2", set.Of[string]("resourceA", "resourceB", "resource2"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1", "resource2"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resourceA", "resourceB", "resource2"), Profit: 1.2},
 			},
 			want: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1", "resource2"), 1.2},
-					{"task2", set.Of[string]("resourceA", "resourceB", "resource2"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1", "resource2"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resourceA", "resourceB", "resource2"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -109,7 +109,7 @@ func TestBuildCompatibilityGraph(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := BuildCompatibilityGraph(tt.tasks); !reflect.DeepEqual(got, tt.want) {
+			if got := BuildCompatibilityGraph(tt.tasks, model.ResourcePolicy{}); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("BuildCompatibilityGraph() = %v, want %v", got, tt.want)
 			}
 		})
@@ -134,8 +134,8 @@ func TestTaskCompatibilityGraph_GetNodes(t1 *testing.T) {
 			name: "Graph with two nodes",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource"), 1.2},
-					{"task2", set.Of[string]("resource"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -174,7 +174,7 @@ func TestTaskCompatibilityGraph_GetNeighbors(t1 *testing.T) {
 			name: "Graph with one node, get neighbors of node",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task", set.Of[string]("resource"), 1.2},
+					{Name: "task", Resources: set.Of[string]("resource"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -187,8 +187,8 @@ func TestTaskCompatibilityGraph_GetNeighbors(t1 *testing.T) {
 			name: "Graph with with two connected node, get neighbors of node",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource2"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Of[int](1),
@@ -228,7 +228,7 @@ func TestTaskCompatibilityGraph_GetWeight(t1 *testing.T) {
 			name: "Graph with one node, get weight of node",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task", set.Of[string]("resource"), 1.2},
+					{Name: "task", Resources: set.Of[string]("resource"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -241,8 +241,8 @@ func TestTaskCompatibilityGraph_GetWeight(t1 *testing.T) {
 			name: "Graph with with two nodes, get weight of node 0",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource2"), 2.4},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 2.4},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Of[int](1),
@@ -282,7 +282,7 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 			name: "Graph with one node, get some nodes not present in graph",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -290,14 +290,14 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 			},
 			nodesToGet: set.Of[int](0, 1, 2),
 			want: []model.Task{
-				{"task1", set.Of[string]("resource1"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
 			},
 		},
 		{
 			name: "Graph with one node, get no nodes",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -310,8 +310,8 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 			name: "Graph with two nodes, get one node",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource1"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource1"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -320,15 +320,15 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 			},
 			nodesToGet: set.Of[int](0),
 			want: []model.Task{
-				{"task1", set.Of[string]("resource1"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
 			},
 		},
 		{
 			name: "Graph with two nodes, get both nodes",
 			graph: TaskCompatibilityGraph{
 				tasks: []model.Task{
-					{"task1", set.Of[string]("resource1"), 1.2},
-					{"task2", set.Of[string]("resource1"), 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource1"), Profit: 1.2},
 				},
 				compatibilityMap: map[int]set.Set[int]{
 					0: set.Empty[int](),
@@ -337,8 +337,8 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 			},
 			nodesToGet: set.Of[int](0, 1),
 			want: []model.Task{
-				{"task1", set.Of[string]("resource1"), 1.2},
-				{"task2", set.Of[string]("resource1"), 1.2},
+				{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resource1"), Profit: 1.2},
 			},
 		},
 	}
@@ -350,3 +350,292 @@ func TestTaskCompatibilityGraph_GetTasksFromNodes(t1 *testing.T) {
 		})
 	}
 }
+
+func TestBuildFleetCompatibilityGraph(t *testing.T) {
+	tests := []struct {
+		name       string
+		tasks      []model.Task
+		satellites []model.Satellite
+		want       TaskCompatibilityGraph
+	}{
+		{
+			name:  "empty list",
+			tasks: []model.Task{},
+			satellites: []model.Satellite{
+				{Name: "sat1", Resources: set.Of[string]("resource")},
+			},
+			want: TaskCompatibilityGraph{
+				tasks:            []model.Task{},
+				compatibilityMap: map[int]set.Set[int]{},
+				satellites:       []string{},
+				origins:          []int{},
+			},
+		},
+		{
+			name: "task without resources in satellite inventory",
+			tasks: []model.Task{
+				{Name: "task1", Resources: set.Of[string]("camera"), Profit: 1.2},
+			},
+			satellites: []model.Satellite{
+				{Name: "sat1", Resources: set.Of[string]("disk")},
+			},
+			want: TaskCompatibilityGraph{
+				tasks:            []model.Task{},
+				compatibilityMap: map[int]set.Set[int]{},
+				satellites:       []string{},
+				origins:          []int{},
+			},
+		},
+		{
+			name: "two incompatible tasks on two satellites",
+			tasks: []model.Task{
+				{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+				{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
+			},
+			satellites: []model.Satellite{
+				{Name: "sat1", Resources: set.Of[string]("resource")},
+				{Name: "sat2", Resources: set.Of[string]("resource")},
+			},
+			want: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Of[int](3),
+					1: set.Of[int](2),
+					2: set.Of[int](1),
+					3: set.Of[int](0),
+				},
+				satellites: []string{"sat1", "sat2", "sat1", "sat2"},
+				origins:    []int{0, 0, 1, 1},
+			},
+		},
+		{
+			name: "task naming its satellite",
+			tasks: []model.Task{
+				{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2, Satellites: set.Of[string]("sat2")},
+				{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
+			},
+			satellites: []model.Satellite{
+				{Name: "sat1", Resources: set.Of[string]("resource")},
+				{Name: "sat2", Resources: set.Empty[string]()},
+			},
+			want: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource"), Profit: 1.2, Satellites: set.Of[string]("sat2")},
+					{Name: "task2", Resources: set.Of[string]("resource"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Of[int](1),
+					1: set.Of[int](0),
+				},
+				satellites: []string{"sat2", "sat1"},
+				origins:    []int{0, 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildFleetCompatibilityGraph(tt.tasks, tt.satellites, model.ResourcePolicy{}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildFleetCompatibilityGraph() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCompatibilityGraph_GetAssignmentsFromNodes(t1 *testing.T) {
+	tests := []struct {
+		name       string
+		graph      TaskCompatibilityGraph
+		nodesToGet set.Set[int]
+		want       []model.Assignment
+	}{
+		{
+			name: "Single platform graph, get node",
+			graph: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Empty[int](),
+				},
+			},
+			nodesToGet: set.Of[int](0, 1),
+			want: []model.Assignment{
+				{Task: model.Task{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2}},
+			},
+		},
+		{
+			name: "Fleet graph, get node",
+			graph: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Empty[int](),
+					1: set.Empty[int](),
+				},
+				satellites: []string{"sat1", "sat2"},
+				origins:    []int{0, 0},
+			},
+			nodesToGet: set.Of[int](1),
+			want: []model.Assignment{
+				{Task: model.Task{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2}, Satellite: "sat2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := tt.graph.GetAssignmentsFromNodes(tt.nodesToGet); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("GetAssignmentsFromNodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCompatibilityGraph_GetTaskIndexesFromNodes(t1 *testing.T) {
+	tests := []struct {
+		name       string
+		graph      TaskCompatibilityGraph
+		nodesToGet set.Set[int]
+		want       set.Set[int]
+	}{
+		{
+			name: "Single platform graph",
+			graph: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Of[int](1),
+					1: set.Of[int](0),
+				},
+			},
+			nodesToGet: set.Of[int](1, 2),
+			want:       set.Of[int](1),
+		},
+		{
+			name: "Fleet graph",
+			graph: TaskCompatibilityGraph{
+				tasks: []model.Task{
+					{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource1"), Profit: 1.2},
+					{Name: "task2", Resources: set.Of[string]("resource1"), Profit: 1.2},
+				},
+				compatibilityMap: map[int]set.Set[int]{
+					0: set.Of[int](2),
+					1: set.Empty[int](),
+					2: set.Of[int](0),
+				},
+				satellites: []string{"sat1", "sat1", "sat2"},
+				origins:    []int{0, 1, 1},
+			},
+			nodesToGet: set.Of[int](0, 2),
+			want:       set.Of[int](0, 1),
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := tt.graph.GetTaskIndexesFromNodes(tt.nodesToGet); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("GetTaskIndexesFromNodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskCompatibilityGraph_WithWeights(t1 *testing.T) {
+	graph := BuildCompatibilityGraph([]model.Task{
+		{Name: "task1", Resources: set.Of[string]("resource1"), Profit: 1.2},
+		{Name: "task2", Resources: set.Of[string]("resource2"), Profit: 2.4, Priority: model.PriorityCritical},
+	}, model.ResourcePolicy{})
+	weightedGraph := graph.WithWeights(func(task model.Task) float64 {
+		if task.Priority == model.PriorityCritical {
+			return task.Profit + 10
+		}
+		return task.Profit
+	})
+
+	if got := weightedGraph.GetWeight(1); got != 12.4 {
+		t1.Errorf("GetWeight() = %v, want %v", got, 12.4)
+	}
+	if got := weightedGraph.GetWeight(0); got != 1.2 {
+		t1.Errorf("GetWeight() = %v, want %v", got, 1.2)
+	}
+	if got := weightedGraph.GetWeight(2); got != 0 {
+		t1.Errorf("GetWeight() of nonexistent node = %v, want %v", got, 0)
+	}
+	if got := graph.GetWeight(1); got != 2.4 {
+		t1.Errorf("GetWeight() of the original graph = %v, want %v", got, 2.4)
+	}
+}
+
+func TestBuildCompatibilityGraph_Conflicts(t *testing.T) {
+	tasks := []model.Task{
+		{Name: "task1", Resources: set.Of[string]("camera", "downlink"), Profit: 1.2},
+		{Name: "task2", Resources: set.Of[string]("disk", "downlink"), Profit: 1.2},
+		{Name: "task3", Resources: set.Of[string]("camera"), Profit: 1.2},
+	}
+	want := TaskCompatibilityGraph{
+		tasks: tasks,
+		compatibilityMap: map[int]set.Set[int]{
+			0: set.Of[int](1),
+			1: set.Of[int](0, 2),
+			2: set.Of[int](1),
+		},
+		penalties: map[int]map[int]float64{
+			0: {1: 0.5},
+			1: {0: 0.5},
+		},
+	}
+	got := BuildCompatibilityGraph(tasks, model.ResourcePolicy{Conflicts: model.ConflictModel{"downlink": 0.5}})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildCompatibilityGraph() = %v, want %v", got, want)
+	}
+	if !got.HasPenalties() {
+		t.Errorf("HasPenalties() = false, want true")
+	}
+	if penalty := got.GetPenalty(1, 0); penalty != 0.5 {
+		t.Errorf("GetPenalty() = %v, want %v", penalty, 0.5)
+	}
+	if penalty := got.GetPenalty(1, 2); penalty != 0 {
+		t.Errorf("GetPenalty() = %v, want %v", penalty, 0)
+	}
+	if total := got.PenaltyTotal(); total != 0.5 {
+		t.Errorf("PenaltyTotal() = %v, want %v", total, 0.5)
+	}
+}
+
+func TestBuildFleetCompatibilityGraph_Conflicts(t *testing.T) {
+	tasks := []model.Task{
+		{Name: "task1", Resources: set.Of[string]("downlink"), Profit: 1.2},
+		{Name: "task2", Resources: set.Of[string]("downlink"), Profit: 1.2},
+	}
+	satellites := []model.Satellite{
+		{Name: "sat1", Resources: set.Of[string]("downlink")},
+		{Name: "sat2", Resources: set.Of[string]("downlink")},
+	}
+	got := BuildFleetCompatibilityGraph(tasks, satellites, model.ResourcePolicy{Conflicts: model.ConflictModel{"downlink": 0.5}})
+	wantPenalties := map[int]map[int]float64{
+		0: {2: 0.5},
+		1: {3: 0.5},
+		2: {0: 0.5},
+		3: {1: 0.5},
+	}
+	if !reflect.DeepEqual(got.penalties, wantPenalties) {
+		t.Errorf("penalties = %v, want %v", got.penalties, wantPenalties)
+	}
+	wantCompatibilityMap := map[int]set.Set[int]{
+		0: set.Of[int](2, 3),
+		1: set.Of[int](2, 3),
+		2: set.Of[int](0, 1),
+		3: set.Of[int](0, 1),
+	}
+	if !reflect.DeepEqual(got.compatibilityMap, wantCompatibilityMap) {
+		t.Errorf("compatibilityMap = %v, want %v", got.compatibilityMap, wantCompatibilityMap)
+	}
+}
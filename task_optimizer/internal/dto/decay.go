@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// Decay describes how the profit of a task drops while it waits. Durations
+// use the Go duration format, e.g. "1h30m".
+type Decay struct {
+	Type        string  `json:"type"`
+	RatePerHour float64 `json:"ratePerHour,omitempty"`
+	HalfLife    string  `json:"halfLife,omitempty"`
+	After       string  `json:"after,omitempty"`
+	Factor      float64 `json:"factor,omitempty"`
+}
+
+func (d Decay) ToModel() (model.Decay, error) {
+	kind, err := model.ParseDecayKind(d.Type)
+	if err != nil {
+		return model.Decay{}, err
+	}
+	decay := model.Decay{
+		Kind:   kind,
+		Rate:   d.RatePerHour,
+		Factor: d.Factor,
+	}
+	if d.HalfLife != "" {
+		if decay.HalfLife, err = time.ParseDuration(d.HalfLife); err != nil {
+			return model.Decay{}, err
+		}
+	}
+	if d.After != "" {
+		if decay.After, err = time.ParseDuration(d.After); err != nil {
+			return model.Decay{}, err
+		}
+	}
+	return decay, decay.Validate()
+}
+
+func DecayFromModel(decay model.Decay) *Decay {
+	if decay.Kind == model.DecayNone {
+		return nil
+	}
+	decayDto := &Decay{
+		Type:        decay.Kind.String(),
+		RatePerHour: decay.Rate,
+		Factor:      decay.Factor,
+	}
+	if decay.HalfLife != 0 {
+		decayDto.HalfLife = decay.HalfLife.String()
+	}
+	if decay.After != 0 {
+		decayDto.After = decay.After.String()
+	}
+	return decayDto
+}
@@ -0,0 +1,5 @@
+package dto
+
+type Error struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"math"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/model"
+)
+
+type TaskRemoval struct {
+	Task   Task   `json:"task"`
+	Reason string `json:"reason"`
+}
+
+// ExecutionFailure is the payload of ExecutionFailed events.
+type ExecutionFailure struct {
+	Request ExecutionRequest `json:"request"`
+	Error   string           `json:"error"`
+}
+
+func ExecutionRequestFromModel(request model.PlanRequest) ExecutionRequest {
+	var executionRequest ExecutionRequest
+	if !math.IsInf(request.EnergyBudget, 1) {
+		energyBudget := request.EnergyBudget
+		executionRequest.EnergyBudget = &energyBudget
+	}
+	if request.Fairness.Policy != model.FairnessNone {
+		executionRequest.Fairness = &Fairness{
+			Policy:          request.Fairness.Policy.String(),
+			MinClientShare:  request.Fairness.MinShare,
+			MaxClientProfit: request.Fairness.MaxClientProfit,
+			ClientWeights:   request.Fairness.ClientWeights,
+		}
+	}
+	return executionRequest
+}
+
+// EventDataFromModel returns the payload of the event.
+func EventDataFromModel(event events.Event) any {
+	switch data := event.Data.(type) {
+	case model.Task:
+		return TaskFromModel(data, event.Time)
+	case events.TaskRemoval:
+		return TaskRemoval{
+			Task:   TaskFromModel(data.Task, event.Time),
+			Reason: string(data.Reason),
+		}
+	case model.PlanRequest:
+		return ExecutionRequestFromModel(data)
+	case model.Plan:
+		return ExecutionFromModel(data)
+	case events.ExecutionFailure:
+		return ExecutionFailure{
+			Request: ExecutionRequestFromModel(data.Request),
+			Error:   data.Err.Error(),
+		}
+	default:
+		return data
+	}
+}
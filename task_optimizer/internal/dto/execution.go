@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"errors"
+	"task_optimizer/internal/model"
+)
+
+type ExecutionRequest struct {
+	EnergyBudget *float64  `json:"energyBudget,omitempty"`
+	Fairness     *Fairness `json:"fairness,omitempty"`
+}
+
+// Fairness selects a fairness Policy among clients: "min-share" (with
+// MinClientShare between 0 and 1), "cap" (with MaxClientProfit) or
+// "proportional" (with optional ClientWeights, 1 by default).
+type Fairness struct {
+	Policy          string             `json:"policy"`
+	MinClientShare  float64            `json:"minClientShare,omitempty"`
+	MaxClientProfit float64            `json:"maxClientProfit,omitempty"`
+	ClientWeights   map[string]float64 `json:"clientWeights,omitempty"`
+}
+
+type ClientShare struct {
+	Client string  `json:"client"`
+	Profit float64 `json:"profit"`
+	Tasks  int     `json:"tasks"`
+}
+
+// Penalty is the profit penalty paid for executing the Tasks (by ID)
+// together, since they share Resources.
+type Penalty struct {
+	Tasks     [2]uint64 `json:"tasks"`
+	Resources []string  `json:"resources"`
+	Penalty   float64   `json:"penalty"`
+}
+
+type Execution struct {
+	Tasks        []Assignment  `json:"tasks"`
+	Clients      []ClientShare `json:"clients"`
+	Penalties    []Penalty     `json:"penalties"`
+	Deferred     []Deferral    `json:"deferred"`
+	PenaltyTotal float64       `json:"penaltyTotal"`
+	EnergyUsed   float64       `json:"energyUsed"`
+	EnergyBudget *float64      `json:"energyBudget,omitempty"`
+	EnergyMargin *float64      `json:"energyMargin,omitempty"`
+	FairnessMet  *bool         `json:"fairnessMet,omitempty"`
+}
+
+func (r ExecutionRequest) ToModel() (model.PlanRequest, error) {
+	request := model.UnconstrainedPlanRequest()
+	if r.EnergyBudget != nil {
+		if *r.EnergyBudget < 0 {
+			return model.PlanRequest{}, errors.New("energy budget can't be negative")
+		}
+		request.EnergyBudget = *r.EnergyBudget
+	}
+	if r.Fairness != nil {
+		policy, err := model.ParseFairnessPolicy(r.Fairness.Policy)
+		if err != nil {
+			return model.PlanRequest{}, err
+		}
+		request.Fairness = model.Fairness{
+			Policy:          policy,
+			MinShare:        r.Fairness.MinClientShare,
+			MaxClientProfit: r.Fairness.MaxClientProfit,
+			ClientWeights:   r.Fairness.ClientWeights,
+		}
+		if err := request.Fairness.Validate(); err != nil {
+			return model.PlanRequest{}, err
+		}
+	}
+	return request, nil
+}
+
+func ExecutionFromModel(plan model.Plan) Execution {
+	execution := Execution{
+		Tasks:        make([]Assignment, 0, len(plan.Assignments)),
+		Clients:      []ClientShare{},
+		Penalties:    make([]Penalty, 0, len(plan.Penalties)),
+		Deferred:     make([]Deferral, 0, len(plan.Deferred)),
+		PenaltyTotal: plan.PenaltyTotal(),
+		EnergyUsed:   plan.EnergyUsed,
+	}
+	for _, penalty := range plan.Penalties {
+		execution.Penalties = append(execution.Penalties, Penalty{
+			Tasks:     [2]uint64{penalty.Tasks[0].ID, penalty.Tasks[1].ID},
+			Resources: penalty.Resources,
+			Penalty:   penalty.Amount,
+		})
+	}
+	for _, deferral := range plan.Deferred {
+		execution.Deferred = append(execution.Deferred, DeferralFromModel(deferral, plan.PlannedAt))
+	}
+	for _, assignment := range plan.Assignments {
+		execution.Tasks = append(execution.Tasks, AssignmentFromModel(assignment, plan.PlannedAt))
+	}
+	for _, share := range plan.ClientShares() {
+		execution.Clients = append(execution.Clients, ClientShare(share))
+	}
+	if plan.HasEnergyBudget() {
+		energyBudget, energyMargin := plan.EnergyBudget, plan.EnergyMargin()
+		execution.EnergyBudget = &energyBudget
+		execution.EnergyMargin = &energyMargin
+	}
+	if plan.Fairness.Policy != model.FairnessNone {
+		fairnessMet := plan.FairnessMet
+		execution.FairnessMet = &fairnessMet
+	}
+	return execution
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"task_optimizer/internal/buildinfo"
+	"task_optimizer/internal/health"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusReady    = "ready"
+	StatusNotReady = "not ready"
+)
+
+type Health struct {
+	Status string `json:"status"`
+}
+
+// Readiness holds the status of the service and the result of each check,
+// "ok" or the error of the check.
+type Readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func ReadinessFromResults(results []health.Result) Readiness {
+	readiness := Readiness{Status: StatusReady, Checks: make(map[string]string, len(results))}
+	if !health.Ready(results) {
+		readiness.Status = StatusNotReady
+	}
+	for _, result := range results {
+		readiness.Checks[result.Name] = StatusOK
+		if result.Err != nil {
+			readiness.Checks[result.Name] = result.Err.Error()
+		}
+	}
+	return readiness
+}
+
+type BuildInfo struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+}
+
+func BuildInfoFromModel(info buildinfo.Info) BuildInfo {
+	return BuildInfo{
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	}
+}
@@ -0,0 +1,15 @@
+package dto
+
+// ImportReport is the outcome of a streaming import of tasks. Valid tasks are
+// imported even if other lines of the stream are invalid.
+type ImportReport struct {
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+	// Errors holds the errors of the first failed lines.
+	Errors []ImportError `json:"errors"`
+}
+
+type ImportError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"task_optimizer/internal/model"
+	"time"
+)
+
+type Namespace struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func NamespaceFromModel(namespace model.Namespace) Namespace {
+	return Namespace{
+		Name:      namespace.Name,
+		CreatedAt: namespace.CreatedAt,
+	}
+}
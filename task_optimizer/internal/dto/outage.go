@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"task_optimizer/internal/model"
+	"time"
+)
+
+type Outage struct {
+	ID       uint64    `json:"id"`
+	Resource string    `json:"resource"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+func (o Outage) ToModel() model.Outage {
+	return model.Outage{
+		Resource: o.Resource,
+		Start:    o.Start,
+		End:      o.End,
+		Reason:   o.Reason,
+	}
+}
+
+func OutageFromModel(outage model.Outage) Outage {
+	return Outage{
+		ID:       outage.ID,
+		Resource: outage.Resource,
+		Start:    outage.Start,
+		End:      outage.End,
+		Reason:   outage.Reason,
+	}
+}
+
+// Deferral is a task left out of an execution because its Resource is
+// unavailable until Until due to the outage with ID OutageID.
+type Deferral struct {
+	Task     Task      `json:"task"`
+	OutageID uint64    `json:"outageId"`
+	Resource string    `json:"resource"`
+	Until    time.Time `json:"until"`
+}
+
+func DeferralFromModel(deferral model.Deferral, now time.Time) Deferral {
+	return Deferral{
+		Task:     TaskFromModel(deferral.Task, now),
+		OutageID: deferral.Outage.ID,
+		Resource: deferral.Outage.Resource,
+		Until:    deferral.Outage.End,
+	}
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+const (
+	DefaultTaskPageLimit = 100
+	MaxTaskPageLimit     = 1000
+)
+
+// TaskQuery filters, sorts and paginates the tasks. Sort is "id" (default),
+// "profit" or "submittedAt", prefixed with "-" for descending order.
+type TaskQuery struct {
+	Resources []string
+	Name      string
+	MinProfit *float64
+	MaxProfit *float64
+	Clients   []string
+	Statuses  []string
+	Sort      string
+	Cursor    string
+	Limit     int
+}
+
+type TaskPage struct {
+	Tasks      []Task `json:"tasks"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func (q TaskQuery) ToModel() (model.TaskQuery, error) {
+	query := model.AllTasksQuery()
+	query.Resources = q.Resources
+	query.Name = q.Name
+	query.Clients = q.Clients
+	query.Cursor = q.Cursor
+	if q.MinProfit != nil {
+		query.MinProfit = *q.MinProfit
+	}
+	if q.MaxProfit != nil {
+		query.MaxProfit = *q.MaxProfit
+	}
+	for _, statusName := range q.Statuses {
+		status, err := model.ParseTaskStatus(statusName)
+		if err != nil {
+			return model.TaskQuery{}, err
+		}
+		query.Statuses = append(query.Statuses, status)
+	}
+	if q.Sort != "" {
+		sortName, descending := strings.CutPrefix(q.Sort, "-")
+		sort, err := model.ParseTaskSort(sortName)
+		if err != nil {
+			return model.TaskQuery{}, err
+		}
+		query.Sort, query.Descending = sort, descending
+	}
+	switch {
+	case q.Limit == 0:
+		query.Limit = DefaultTaskPageLimit
+	case q.Limit < 0 || q.Limit > MaxTaskPageLimit:
+		return model.TaskQuery{}, errors.New("page limit must be between 1 and 1000")
+	default:
+		query.Limit = q.Limit
+	}
+	return query, nil
+}
+
+func TaskPageFromModel(page model.TaskPage, now time.Time) TaskPage {
+	pageDto := TaskPage{
+		Tasks:      make([]Task, 0, len(page.Tasks)),
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+	}
+	for _, task := range page.Tasks {
+		pageDto.Tasks = append(pageDto.Tasks, TaskFromModel(task, now))
+	}
+	return pageDto
+}
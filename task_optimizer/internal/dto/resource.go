@@ -0,0 +1,24 @@
+package dto
+
+import "task_optimizer/internal/model"
+
+type Resource struct {
+	Name              string `json:"name"`
+	Parent            string `json:"parent,omitempty"`
+	ExclusiveChildren bool   `json:"exclusiveChildren"`
+}
+
+func (r Resource) ToModel() model.Resource {
+	return model.Resource{
+		Name:              r.Name,
+		ExclusiveChildren: r.ExclusiveChildren,
+	}
+}
+
+func ResourceFromModel(resource model.Resource) Resource {
+	return Resource{
+		Name:              resource.Name,
+		Parent:            resource.Parent(),
+		ExclusiveChildren: resource.ExclusiveChildren,
+	}
+}
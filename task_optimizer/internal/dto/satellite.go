@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/model"
+)
+
+type Satellite struct {
+	Name      string   `json:"name"`
+	Resources []string `json:"resources"`
+}
+
+func (s Satellite) ToModel() model.Satellite {
+	return model.Satellite{
+		Name:      s.Name,
+		Resources: set.Of(s.Resources...),
+	}
+}
+
+func SatelliteFromModel(satellite model.Satellite) Satellite {
+	return Satellite{
+		Name:      satellite.Name,
+		Resources: satellite.Resources.Slice(),
+	}
+}
@@ -1,28 +1,119 @@
 package dto
 
 import (
+	"errors"
 	"task_optimizer/internal/ds/set"
 	"task_optimizer/internal/model"
+	"time"
 )
 
 type Task struct {
+	ID        uint64   `json:"id"`
 	Name      string   `json:"name"`
+	Client    string   `json:"client,omitempty"`
 	Resources []string `json:"resources"`
 	Profit    float64  `json:"profit"`
+	// EffectiveProfit is the profit once decayed, only set in responses.
+	EffectiveProfit float64  `json:"effectiveProfit"`
+	Decay           *Decay   `json:"decay,omitempty"`
+	Satellites      []string `json:"satellites,omitempty"`
+	Energy          float64  `json:"energy"`
+	// Priority is the SLA class of the task: critical, standard (default) or
+	// best-effort.
+	Priority    string     `json:"priority,omitempty"`
+	SubmittedAt time.Time  `json:"submittedAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Pinned      bool       `json:"pinned,omitempty"`
+	Held        bool       `json:"held,omitempty"`
+	// Status is pending, pinned, held or expired, only set in responses.
+	Status string `json:"status,omitempty"`
+	// TemplateID is the template that enqueued the task, only set in
+	// responses.
+	TemplateID uint64 `json:"templateId,omitempty"`
+	// SubmittedBy is the API key that added the task, only set in responses.
+	SubmittedBy string `json:"submittedBy,omitempty"`
 }
 
-func (t Task) ToModel() model.Task {
-	return model.Task{
-		Name:      t.Name,
-		Resources: set.Of(t.Resources...),
-		Profit:    t.Profit,
+// TaskOverrides toggles the pinned and held flags of a task, flags that are
+// not present are left unchanged.
+type TaskOverrides struct {
+	Pinned *bool `json:"pinned,omitempty"`
+	Held   *bool `json:"held,omitempty"`
+}
+
+type Assignment struct {
+	Task
+	Satellite string `json:"satellite,omitempty"`
+}
+
+func (t Task) ToModel() (model.Task, error) {
+	priority := model.PriorityStandard
+	if t.Priority != "" {
+		var err error
+		if priority, err = model.ParsePriority(t.Priority); err != nil {
+			return model.Task{}, err
+		}
+	}
+	task := model.Task{
+		Name:       t.Name,
+		Client:     t.Client,
+		Resources:  set.Of(t.Resources...),
+		Profit:     t.Profit,
+		Satellites: set.Of(t.Satellites...),
+		Energy:     t.Energy,
+		Priority:   priority,
+		Pinned:     t.Pinned,
+		Held:       t.Held,
+	}
+	if task.Pinned && task.Held {
+		return model.Task{}, errors.New("task can't be pinned and held at the same time")
+	}
+	if task.Energy < 0 {
+		// The search prunes the plans over the energy budget, which is only
+		// right if adding tasks never lowers the energy.
+		return model.Task{}, errors.New("task energy can't be negative")
+	}
+	if t.ExpiresAt != nil {
+		task.ExpiresAt = *t.ExpiresAt
+	}
+	if t.Decay != nil {
+		var err error
+		if task.Decay, err = t.Decay.ToModel(); err != nil {
+			return model.Task{}, err
+		}
+	}
+	return task, nil
+}
+
+// TaskFromModel converts the task, reporting its effective profit at now.
+func TaskFromModel(task model.Task, now time.Time) Task {
+	taskDto := Task{
+		ID:              task.ID,
+		Name:            task.Name,
+		Client:          task.Client,
+		Resources:       task.Resources.Slice(),
+		Profit:          task.Profit,
+		EffectiveProfit: task.EffectiveProfit(now),
+		Decay:           DecayFromModel(task.Decay),
+		Satellites:      task.Satellites.Slice(),
+		Energy:          task.Energy,
+		Priority:        task.Priority.String(),
+		SubmittedAt:     task.SubmittedAt,
+		Pinned:          task.Pinned,
+		Held:            task.Held,
+		TemplateID:      task.TemplateID,
+		SubmittedBy:     task.SubmittedBy,
+		Status:          task.Status(now).String(),
+	}
+	if !task.ExpiresAt.IsZero() {
+		taskDto.ExpiresAt = &task.ExpiresAt
 	}
+	return taskDto
 }
 
-func TaskFromModel(task model.Task) Task {
-	return Task{
-		Name:      task.Name,
-		Resources: task.Resources.Slice(),
-		Profit:    task.Profit,
+func AssignmentFromModel(assignment model.Assignment, now time.Time) Assignment {
+	return Assignment{
+		Task:      TaskFromModel(assignment.Task, now),
+		Satellite: assignment.Satellite,
 	}
 }
@@ -0,0 +1,44 @@
+package dto
+
+import "testing"
+
+func TestTask_ToModel_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		task Task
+	}{
+		{"invalid priority", Task{Name: "a", Priority: "urgent"}},
+		{"pinned and held", Task{Name: "a", Pinned: true, Held: true}},
+		{"negative energy", Task{Name: "a", Energy: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.task.ToModel(); err == nil {
+				t.Error("ToModel() succeeded, want error")
+			}
+			template := TaskTemplate{Name: "daily", Interval: "24h", Task: tt.task}
+			if _, err := template.ToModel(); err == nil {
+				t.Error("template ToModel() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestTaskTemplate_ToModel_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		template TaskTemplate
+	}{
+		{"no schedule", TaskTemplate{Name: "daily", Task: Task{Name: "a"}}},
+		{"interval and cron", TaskTemplate{Name: "daily", Interval: "24h", Cron: "0 3 * * *", Task: Task{Name: "a"}}},
+		{"negative interval", TaskTemplate{Name: "daily", Interval: "-1h", Task: Task{Name: "a"}}},
+		{"negative expiresAfter", TaskTemplate{Name: "daily", Interval: "24h", ExpiresAfter: "-1h", Task: Task{Name: "a"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.template.ToModel(); err == nil {
+				t.Error("ToModel() succeeded, want error")
+			}
+		})
+	}
+}
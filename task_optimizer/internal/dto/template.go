@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"errors"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/schedule"
+	"time"
+)
+
+// TaskTemplate enqueues Task on a schedule given either as a fixed Interval
+// (e.g. "24h") or as a Cron expression (e.g. "0 3 * * *").
+type TaskTemplate struct {
+	ID           uint64    `json:"id"`
+	Name         string    `json:"name"`
+	Interval     string    `json:"interval,omitempty"`
+	Cron         string    `json:"cron,omitempty"`
+	ExpiresAfter string    `json:"expiresAfter,omitempty"`
+	Task         Task      `json:"task"`
+	NextRun      time.Time `json:"nextRun"`
+}
+
+func (t TaskTemplate) ToModel() (model.TaskTemplate, error) {
+	task, err := t.Task.ToModel()
+	if err != nil {
+		return model.TaskTemplate{}, err
+	}
+	template := model.TaskTemplate{
+		Name: t.Name,
+		Task: task,
+		Cron: t.Cron,
+	}
+	switch {
+	case t.Interval != "" && t.Cron != "":
+		return model.TaskTemplate{}, errors.New("template must have either an interval or a cron schedule, not both")
+	case t.Interval != "":
+		if template.Interval, err = time.ParseDuration(t.Interval); err != nil {
+			return model.TaskTemplate{}, err
+		}
+		if template.Interval <= 0 {
+			return model.TaskTemplate{}, errors.New("template interval must be positive")
+		}
+		template.Schedule = schedule.Interval(template.Interval)
+	case t.Cron != "":
+		if template.Schedule, err = schedule.ParseCron(t.Cron); err != nil {
+			return model.TaskTemplate{}, err
+		}
+	default:
+		return model.TaskTemplate{}, errors.New("template must have an interval or a cron schedule")
+	}
+	if t.ExpiresAfter != "" {
+		if template.ExpiresAfter, err = time.ParseDuration(t.ExpiresAfter); err != nil {
+			return model.TaskTemplate{}, err
+		}
+		if template.ExpiresAfter < 0 {
+			return model.TaskTemplate{}, errors.New("template expiresAfter can't be negative")
+		}
+	}
+	return template, nil
+}
+
+func TaskTemplateFromModel(template model.TaskTemplate) TaskTemplate {
+	templateDto := TaskTemplate{
+		ID:      template.ID,
+		Name:    template.Name,
+		Cron:    template.Cron,
+		Task:    TaskFromModel(template.Task, template.Task.SubmittedAt),
+		NextRun: template.NextRun,
+	}
+	if template.Interval != 0 {
+		templateDto.Interval = template.Interval.String()
+	}
+	if template.ExpiresAfter != 0 {
+		templateDto.ExpiresAfter = template.ExpiresAfter.String()
+	}
+	return templateDto
+}
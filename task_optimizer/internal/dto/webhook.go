@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// Webhook is a subscription to the executions. The Secret signing the
+// deliveries is never returned.
+type Webhook struct {
+	ID     uint64 `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"secret,omitempty"`
+}
+
+func (w Webhook) ToModel() model.Webhook {
+	return model.Webhook{
+		URL:    w.URL,
+		Secret: w.Secret,
+	}
+}
+
+func WebhookFromModel(webhook model.Webhook) Webhook {
+	return Webhook{
+		ID:  webhook.ID,
+		URL: webhook.URL,
+	}
+}
+
+// WebhookDelivery is the body posted to webhooks on each execution.
+type WebhookDelivery struct {
+	EventID   uint64    `json:"eventId"`
+	Time      time.Time `json:"time"`
+	Execution Execution `json:"execution"`
+}
+
+type DeadLetter struct {
+	ID        uint64    `json:"id"`
+	WebhookID uint64    `json:"webhookId"`
+	URL       string    `json:"url"`
+	EventID   uint64    `json:"eventId"`
+	Payload   string    `json:"payload"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	FailedAt  time.Time `json:"failedAt"`
+}
+
+func DeadLetterFromModel(deadLetter model.DeadLetter) DeadLetter {
+	return DeadLetter{
+		ID:        deadLetter.ID,
+		WebhookID: deadLetter.WebhookID,
+		URL:       deadLetter.URL,
+		EventID:   deadLetter.EventID,
+		Payload:   string(deadLetter.Payload),
+		Attempts:  deadLetter.Attempts,
+		Error:     deadLetter.Error,
+		FailedAt:  deadLetter.FailedAt,
+	}
+}
@@ -0,0 +1,262 @@
+package events
+
+import (
+	"sync"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+type Type string
+
+const (
+	TaskAdded          Type = "TaskAdded"
+	TaskRemoved        Type = "TaskRemoved"
+	ExecutionStarted   Type = "ExecutionStarted"
+	ExecutionCompleted Type = "ExecutionCompleted"
+	ExecutionFailed    Type = "ExecutionFailed"
+)
+
+// Event is a change of the service state. Data is a model.Task for
+// TaskAdded, a TaskRemoval for TaskRemoved, a model.PlanRequest for
+// ExecutionStarted, a model.Plan for ExecutionCompleted and an
+// ExecutionFailure for ExecutionFailed.
+type Event struct {
+	ID   uint64
+	Type Type
+	Time time.Time
+	Data any
+}
+
+type RemovalReason string
+
+const (
+	RemovalExecuted RemovalReason = "executed"
+	RemovalExpired  RemovalReason = "expired"
+)
+
+type TaskRemoval struct {
+	Task   model.Task
+	Reason RemovalReason
+}
+
+type ExecutionFailure struct {
+	Request model.PlanRequest
+	Err     error
+}
+
+// maxSubscriptionLag is the number of events a subscriber can lag behind
+// before it is dropped, more than a request adds or executes at once.
+const maxSubscriptionLag = 1 << 16
+
+// Bus publishes events to its subscribers, keeping the last events in a ring
+// buffer so that subscribers can resume from the last event they received.
+// Publishing never blocks, subscribers that lag too far behind are dropped
+// and have to resume.
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	buffer      []Event
+	next        int
+	subscribers map[*Subscription]struct{}
+	// closed is set by Close, subscriptions end right away from then on.
+	closed bool
+}
+
+// NewBus returns a bus keeping the last capacity events.
+func NewBus(capacity int) *Bus {
+	return &Bus{
+		buffer:      make([]Event, 0, capacity),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish assigns the next ID to the event, stores it and queues it for the
+// subscribers.
+func (b *Bus) Publish(eventType Type, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event := Event{
+		ID:   b.lastID,
+		Type: eventType,
+		Time: time.Now(),
+		Data: data,
+	}
+	if len(b.buffer) < cap(b.buffer) {
+		b.buffer = append(b.buffer, event)
+	} else if len(b.buffer) > 0 {
+		b.buffer[b.next] = event
+		b.next = (b.next + 1) % len(b.buffer)
+	}
+	for subscription := range b.subscribers {
+		if !subscription.accepts(event) {
+			continue
+		}
+		if subscription.maxLag > 0 && len(subscription.pending) >= subscription.maxLag {
+			b.unsubscribe(subscription)
+			continue
+		}
+		subscription.pending = append(subscription.pending, event)
+		subscription.signal()
+	}
+	return event
+}
+
+// Subscribe returns a subscription to the events published from now on and
+// the buffered events after lastEventID, oldest first. Events older than the
+// buffer are lost.
+func (b *Bus) Subscribe(lastEventID uint64) (*Subscription, []Event) {
+	return b.subscribe(lastEventID, maxSubscriptionLag, nil)
+}
+
+// SubscribeReliable subscribes to the events of the types given like
+// Subscribe, but the subscription is never dropped for lagging behind. It's
+// meant for subscribers that must see every event of rare types, such as the
+// webhook dispatcher.
+func (b *Bus) SubscribeReliable(lastEventID uint64, types ...Type) (*Subscription, []Event) {
+	accepted := make(map[Type]bool, len(types))
+	for _, eventType := range types {
+		accepted[eventType] = true
+	}
+	return b.subscribe(lastEventID, 0, accepted)
+}
+
+func (b *Bus) subscribe(lastEventID uint64, maxLag int, types map[Type]bool) (*Subscription, []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscription := &Subscription{
+		bus:    b,
+		types:  types,
+		maxLag: maxLag,
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		events: make(chan Event),
+	}
+	var missed []Event
+	for i := range b.buffer {
+		event := b.buffer[(b.next+i)%len(b.buffer)]
+		if event.ID > lastEventID && subscription.accepts(event) {
+			missed = append(missed, event)
+		}
+	}
+	go subscription.deliver()
+	if b.closed {
+		subscription.dropped = true
+		subscription.signal()
+		return subscription, missed
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription, missed
+}
+
+// LastID returns the ID of the last event published.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Close ends every subscription once their pending events are delivered, and
+// the subscriptions made from then on right away.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for subscription := range b.subscribers {
+		b.unsubscribe(subscription)
+	}
+}
+
+// unsubscribe removes the subscription, whose channel is closed once its
+// pending events are delivered. The caller must hold mu.
+func (b *Bus) unsubscribe(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; ok {
+		delete(b.subscribers, subscription)
+		subscription.dropped = true
+		subscription.signal()
+	}
+}
+
+type Subscription struct {
+	bus *Bus
+	// types are the types of the events of the subscription, all of them
+	// if nil.
+	types map[Type]bool
+	// maxLag is the number of pending events over which the subscription is
+	// dropped, 0 to never drop it.
+	maxLag int
+
+	// pending holds the events published and not yet delivered, and dropped
+	// whether the subscription was removed from the bus. Both are guarded by
+	// the mutex of the bus.
+	pending []Event
+	dropped bool
+
+	// notify wakes up deliver when there are pending events or the
+	// subscription is dropped, and done is closed by Close.
+	notify    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	events    chan Event
+}
+
+// Events returns the channel of published events, closed when the
+// subscription is closed, the bus is closed or the subscription is dropped
+// for lagging behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	s.bus.unsubscribe(s)
+	s.bus.mu.Unlock()
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// BusClosed reports whether the bus was closed, to tell a subscription ended
+// by Bus.Close from one dropped for lagging behind.
+func (s *Subscription) BusClosed() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.bus.closed
+}
+
+func (s *Subscription) accepts(event Event) bool {
+	return s.types == nil || s.types[event.Type]
+}
+
+// signal wakes up deliver, if it isn't already.
+func (s *Subscription) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// deliver sends the pending events to the channel of the subscription until
+// it's dropped or closed.
+func (s *Subscription) deliver() {
+	defer close(s.events)
+	for {
+		select {
+		case <-s.notify:
+		case <-s.done:
+			return
+		}
+		s.bus.mu.Lock()
+		pending, dropped := s.pending, s.dropped
+		s.pending = nil
+		s.bus.mu.Unlock()
+		for _, event := range pending {
+			select {
+			case s.events <- event:
+			case <-s.done:
+				return
+			}
+		}
+		if dropped {
+			return
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+func eventIDs(events []Event) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestBus_Subscribe(t *testing.T) {
+	tests := []struct {
+		name        string
+		capacity    int
+		published   int
+		lastEventID uint64
+		want        []uint64
+	}{
+		{"no events", 3, 0, 0, []uint64{}},
+		{"all buffered", 3, 2, 0, []uint64{1, 2}},
+		{"resume", 3, 3, 1, []uint64{2, 3}},
+		{"up to date", 3, 3, 3, []uint64{}},
+		{"buffer overwritten", 3, 5, 0, []uint64{3, 4, 5}},
+		{"resume after overwrite", 3, 7, 5, []uint64{6, 7}},
+		{"no buffer", 0, 3, 0, []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewBus(tt.capacity)
+			for i := 0; i < tt.published; i++ {
+				bus.Publish(TaskAdded, nil)
+			}
+			subscription, missed := bus.Subscribe(tt.lastEventID)
+			defer subscription.Close()
+			if got := eventIDs(missed); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Subscribe() missed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus(8)
+	subscription, _ := bus.Subscribe(0)
+	defer subscription.Close()
+	bus.Publish(TaskAdded, "a")
+	bus.Publish(TaskRemoved, "b")
+
+	for _, want := range []Event{{ID: 1, Type: TaskAdded, Data: "a"}, {ID: 2, Type: TaskRemoved, Data: "b"}} {
+		got := <-subscription.Events()
+		if got.ID != want.ID || got.Type != want.Type || got.Data != want.Data {
+			t.Errorf("Events() = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestBus_Publish_Batch(t *testing.T) {
+	bus := NewBus(8)
+	subscription, _ := bus.Subscribe(0)
+	defer subscription.Close()
+	// A batch published at once, such as an import, doesn't overflow a
+	// subscriber that keeps up.
+	const published = 1000
+	for i := 0; i < published; i++ {
+		bus.Publish(TaskAdded, nil)
+	}
+
+	for want := uint64(1); want <= published; want++ {
+		event, ok := <-subscription.Events()
+		if !ok {
+			t.Fatalf("subscription dropped after %d events", want-1)
+		}
+		if event.ID != want {
+			t.Fatalf("Events() = %d, want %d", event.ID, want)
+		}
+	}
+}
+
+func TestBus_Publish_DropsLaggingSubscriber(t *testing.T) {
+	bus := NewBus(8)
+	subscription, _ := bus.Subscribe(0)
+	for i := 0; i < 2*maxSubscriptionLag; i++ {
+		bus.Publish(TaskAdded, nil)
+	}
+
+	received := 0
+	for range subscription.Events() {
+		received++
+	}
+	if received < maxSubscriptionLag || received >= 2*maxSubscriptionLag {
+		t.Errorf("received %d events, want the %d pending when dropped", received, maxSubscriptionLag)
+	}
+	subscription.Close()
+}
+
+func TestSubscription_Close(t *testing.T) {
+	bus := NewBus(8)
+	subscription, _ := bus.Subscribe(0)
+	bus.Publish(TaskAdded, nil)
+	subscription.Close()
+	subscription.Close()
+	bus.Publish(TaskAdded, nil)
+	for range subscription.Events() {
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus(8)
+	subscription, _ := bus.Subscribe(0)
+	defer subscription.Close()
+	bus.Publish(TaskAdded, nil)
+	bus.Close()
+	bus.Publish(TaskAdded, nil)
+
+	// The event pending when the bus was closed is delivered, the next one
+	// isn't.
+	if got := eventIDs(collect(subscription)); !reflect.DeepEqual(got, []uint64{1}) {
+		t.Errorf("Events() = %v, want [1]", got)
+	}
+	if !subscription.BusClosed() {
+		t.Errorf("BusClosed() = false after Close()")
+	}
+	late, missed := bus.Subscribe(0)
+	defer late.Close()
+	if got := eventIDs(missed); !reflect.DeepEqual(got, []uint64{1, 2}) {
+		t.Errorf("Subscribe() after Close() missed = %v, want [1 2]", got)
+	}
+	if got := collect(late); len(got) != 0 {
+		t.Errorf("Events() after Close() = %v, want none", eventIDs(got))
+	}
+}
+
+// collect returns the events of the subscription until its channel is
+// closed.
+func collect(subscription *Subscription) []Event {
+	var events []Event
+	for event := range subscription.Events() {
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestBus_SubscribeReliable(t *testing.T) {
+	bus := NewBus(8)
+	bus.Publish(ExecutionCompleted, nil)
+	subscription, missed := bus.SubscribeReliable(0, ExecutionCompleted)
+	defer subscription.Close()
+	if got := eventIDs(missed); !reflect.DeepEqual(got, []uint64{1}) {
+		t.Errorf("SubscribeReliable() missed = %v, want [1]", got)
+	}
+	// Way more events than a subscription can lag behind, and than the
+	// buffer keeps, before the execution.
+	for i := 0; i < 2*maxSubscriptionLag; i++ {
+		bus.Publish(ExecutionCompleted, nil)
+	}
+	for i := 0; i < 100; i++ {
+		bus.Publish(TaskRemoved, nil)
+	}
+	last := bus.Publish(ExecutionCompleted, nil)
+
+	received := 0
+	for event := range subscription.Events() {
+		if event.Type != ExecutionCompleted {
+			t.Fatalf("Events() = %s, want only %s", event.Type, ExecutionCompleted)
+		}
+		received++
+		if event.ID == last.ID {
+			break
+		}
+	}
+	if received != 2*maxSubscriptionLag+1 {
+		t.Errorf("received %d executions, want %d", received, 2*maxSubscriptionLag+1)
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/rs/zerolog"
+	"net/http"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/logging"
+)
+
+// APIKeyHeader is the header holding the API key of the caller, which can
+// also be sent as a bearer token in the Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+// Authorized calls next only for callers allowed the operations of role,
+// adding their identity to the context of the request and their name to its
+// logger. Other callers get a 401 or 403 response. A nil authenticator
+// allows every caller.
+func Authorized(authenticator *auth.Authenticator, role auth.Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, err := authenticator.Authorize(requestKey(r), role)
+		logger := logging.FromContext(r.Context())
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("caller", identity.Name)
+		})
+		if err != nil {
+			logger.Warn().Err(err).Msg("request unauthorized")
+			status := http.StatusUnauthorized
+			if errors.Is(err, auth.ErrForbidden) {
+				status = http.StatusForbidden
+			} else {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(dto.Error{Error: err.Error()})
+			return
+		}
+		next(w, r.WithContext(auth.NewContext(r.Context(), identity)))
+	}
+}
+
+func requestKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	return auth.KeyFromAuthorization(r.Header.Get("Authorization"))
+}
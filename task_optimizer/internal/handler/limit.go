@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/logging"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/ratelimit"
+	"time"
+)
+
+// Limits bound the requests of each caller.
+type Limits struct {
+	// RateLimit is the rate limit of each API key, unless the key overrides
+	// it, and of each client address when authentication is disabled.
+	RateLimit ratelimit.Limit
+	// MaxBodyBytes is the size of the largest request body, 0 for no limit.
+	MaxBodyBytes int64
+}
+
+// Limiter rejects the requests over the limits.
+type Limiter struct {
+	limits  Limits
+	buckets *ratelimit.Limiter
+	metrics *metrics.HTTPMetrics
+}
+
+func NewLimiter(limits Limits, httpMetrics *metrics.HTTPMetrics) *Limiter {
+	return &Limiter{
+		limits:  limits,
+		buckets: ratelimit.NewLimiter(),
+		metrics: httpMetrics,
+	}
+}
+
+// Limited calls next only for callers within their rate limit, with the body
+// of the request bounded by the size limit. Callers over their rate limit get
+// a 429 response with Retry-After, and requests whose Content-Length is over
+// the size limit a 413 response. Controllers reading bodies over the limit
+// get an *http.MaxBytesError and respond 413 too. It must be called within
+// Authorized, so that requests are limited by API key.
+func (l *Limiter) Limited(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity := auth.FromContext(r.Context())
+		limit := l.limits.RateLimit
+		if identity.RateLimit != nil {
+			limit = *identity.RateLimit
+		}
+		if ok, retryAfter := l.buckets.Allow(rateLimitKey(r, identity), limit); !ok {
+			l.reject(w, r, "rate_limit", http.StatusTooManyRequests, retryAfter, errors.New("rate limit exceeded"))
+			return
+		}
+
+		if l.limits.MaxBodyBytes <= 0 {
+			next(w, r)
+			return
+		}
+		if r.ContentLength > l.limits.MaxBodyBytes {
+			l.reject(w, r, "body_size", http.StatusRequestEntityTooLarge, 0,
+				fmt.Errorf("request body over %d bytes", l.limits.MaxBodyBytes))
+			return
+		}
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, l.limits.MaxBodyBytes)}
+		r.Body = body
+		recorder := &statusRecorder{ResponseWriter: w}
+		next(recorder, r)
+		if recorder.status == http.StatusRequestEntityTooLarge {
+			reason := "task_count"
+			if body.exceeded {
+				reason = "body_size"
+			}
+			l.metrics.RejectedRequests.WithLabelValues(reason).Inc()
+		}
+	}
+}
+
+func (l *Limiter) reject(w http.ResponseWriter, r *http.Request, reason string, status int, retryAfter time.Duration, err error) {
+	logging.FromContext(r.Context()).Warn().Err(err).Msg("request rejected")
+	l.metrics.RejectedRequests.WithLabelValues(reason).Inc()
+	if retryAfter > 0 {
+		// Retry-After is in whole seconds, rounded up so that the retry
+		// finds a token.
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.Error{Error: err.Error()})
+}
+
+// rateLimitKey returns the key of the rate limit bucket of the caller: its
+// API key, or its address if it's anonymous.
+func rateLimitKey(r *http.Request, identity auth.Identity) string {
+	if identity.Name != "" {
+		return "key:" + identity.Name
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// limitedBody records whether the body went over the size limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// statusRecorder records the status and size of a response. It keeps the
+// response flushable for the streaming handlers.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+// Status returns the status of the response, 200 if it wasn't set.
+func (r *statusRecorder) Status() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
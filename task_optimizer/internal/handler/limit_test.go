@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	prometheusmodel "github.com/prometheus/client_model/go"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/ratelimit"
+	"testing"
+)
+
+var httpMetrics = metrics.NewHTTPMetrics()
+
+func rejections(t *testing.T, reason string) float64 {
+	t.Helper()
+	var metric prometheusmodel.Metric
+	if err := httpMetrics.RejectedRequests.WithLabelValues(reason).Write(&metric); err != nil {
+		t.Fatal(err)
+	}
+	return metric.GetCounter().GetValue()
+}
+
+func TestLimiter_RateLimit(t *testing.T) {
+	limiter := NewLimiter(Limits{RateLimit: ratelimit.Limit{Rate: 0.5, Burst: 2}}, httpMetrics)
+	handler := limiter.Limited(func(w http.ResponseWriter, r *http.Request) {})
+	before := rejections(t, "rate_limit")
+
+	wantStatuses := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, want := range wantStatuses {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		request = request.WithContext(auth.NewContext(request.Context(), auth.Identity{Name: "planner", Role: auth.RoleSubmitter}))
+		handler(recorder, request)
+		if recorder.Code != want {
+			t.Errorf("request %d: status = %d, want %d", i+1, recorder.Code, want)
+		}
+		if want == http.StatusTooManyRequests && recorder.Header().Get("Retry-After") != "2" {
+			t.Errorf("request %d: Retry-After = %q, want 2", i+1, recorder.Header().Get("Retry-After"))
+		}
+	}
+
+	// Other callers and keys with their own limit have their own buckets.
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("anonymous caller: status = %d, want 200", recorder.Code)
+	}
+	unlimited := auth.Identity{Name: "ops", Role: auth.RoleOperator, RateLimit: &ratelimit.Limit{}}
+	for i := 0; i < 5; i++ {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		handler(recorder, request.WithContext(auth.NewContext(request.Context(), unlimited)))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("unlimited key: status = %d, want 200", recorder.Code)
+		}
+	}
+	if got := rejections(t, "rate_limit") - before; got != 1 {
+		t.Errorf("rate_limit rejections = %v, want 1", got)
+	}
+}
+
+func TestLimiter_BodySize(t *testing.T) {
+	limiter := NewLimiter(Limits{MaxBodyBytes: 8}, httpMetrics)
+	handler := limiter.Limited(ToHandlerFunc(func(w http.ResponseWriter, r *http.Request) (int, any) {
+		var body any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return http.StatusRequestEntityTooLarge, nil
+		}
+		return http.StatusOK, nil
+	}))
+	before := rejections(t, "body_size")
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength bool
+		want          int
+	}{
+		{"within limit", `[1, 2]`, true, http.StatusOK},
+		{"content length over limit", `[1, 2, 3, 4]`, true, http.StatusRequestEntityTooLarge},
+		{"chunked body over limit", `[1, 2, 3, 4]`, false, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if !tt.contentLength {
+				body = io.MultiReader(body)
+			}
+			recorder := httptest.NewRecorder()
+			handler(recorder, httptest.NewRequest(http.MethodPost, "/tasks", body))
+			if recorder.Code != tt.want {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+	if got := rejections(t, "body_size") - before; got != 2 {
+		t.Errorf("body_size rejections = %v, want 2", got)
+	}
+}
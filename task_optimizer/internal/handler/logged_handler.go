@@ -2,31 +2,73 @@ package handler
 
 import (
 	"encoding/json"
-	"github.com/rs/zerolog/log"
+	"io"
 	"net/http"
+	"task_optimizer/internal/logging"
+	"time"
 )
 
+// RequestIDHeader is the header holding the ID of a request, given by the
+// client or else generated, and echoed back in the response.
+const RequestIDHeader = "X-Request-ID"
+
 type ControllerHandler func(w http.ResponseWriter, r *http.Request) (int, any)
 
-func ToLoggedHandlerFunc(controllerHandler ControllerHandler) http.HandlerFunc {
+// ToHandlerFunc responds with the status returned by the controller handler
+// and its body, if not nil, as JSON.
+func ToHandlerFunc(controllerHandler ControllerHandler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		logger := log.With().
-			Str("path", r.URL.String()).
-			Str("method", r.Method).Logger()
-		logger.Info().Msg("request started")
-
 		status, body := controllerHandler(w, r)
+		if body == nil {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(status)
-		if body != nil {
-			w.Header().Set("Content-Type", "application/json")
-			err := json.NewEncoder(w).Encode(body)
-			if err != nil {
-				logger.Err(err).Send()
-			}
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			logging.FromContext(r.Context()).Err(err).Send()
 		}
+	}
+}
+
+// Logged logs the start and completion of the requests handled by next, with
+// their latency, status and the size of their body and response. next gets
+// the logger of the request in its context, tagged with the request ID, path,
+// method and remote address, and the caller once Authorized identifies it.
+func Logged(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		startTime := time.Now()
+		requestID := logging.RequestID(r.Header.Get(RequestIDHeader))
+		w.Header().Set(RequestIDHeader, requestID)
+		logger := logging.FromContext(r.Context()).With().
+			Str("request_id", requestID).
+			Str("path", r.URL.String()).
+			Str("method", r.Method).
+			Str("remote_addr", r.RemoteAddr).Logger()
+		logger.Info().Msg("request started")
+
+		body := &countedBody{ReadCloser: r.Body}
+		r.Body = body
+		recorder := &statusRecorder{ResponseWriter: w}
+		next(recorder, r.WithContext(logging.WithLogger(r.Context(), &logger)))
 
 		logger.Info().
-			Int("status", status).
+			Int("status", recorder.Status()).
+			Dur("duration", time.Since(startTime)).
+			Int64("request_size", body.size).
+			Int64("response_size", recorder.size).
 			Msg("request completed")
 	}
 }
+
+// countedBody counts the bytes read from a request body.
+type countedBody struct {
+	io.ReadCloser
+	size int64
+}
+
+func (b *countedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
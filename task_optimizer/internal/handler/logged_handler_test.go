@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/logging"
+	"testing"
+)
+
+func TestLogged(t *testing.T) {
+	var logs bytes.Buffer
+	globalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = globalLogger }()
+
+	authenticator, err := auth.NewAuthenticator(auth.KeyFile{Keys: []auth.KeyEntry{{Name: "planner", Role: "submitter", Key: "secret"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := Logged(Authorized(authenticator, auth.RoleSubmitter, ToHandlerFunc(func(w http.ResponseWriter, r *http.Request) (int, any) {
+		var tasks []any
+		if err := json.NewDecoder(r.Body).Decode(&tasks); err != nil {
+			return http.StatusBadRequest, nil
+		}
+		logging.FromContext(r.Context()).Info().Msg("tasks added")
+		return http.StatusCreated, map[string]int{"added": len(tasks)}
+	})))
+
+	tests := []struct {
+		name          string
+		requestID     string
+		wantRequestID bool
+	}{
+		{"given ID", "7d2f4c1e", true},
+		{"no ID", "", false},
+		{"invalid ID", "forged\tid", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			request := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`[{}, {}]`))
+			request.Header.Set(APIKeyHeader, "secret")
+			if tt.requestID != "" {
+				request.Header.Set(RequestIDHeader, tt.requestID)
+			}
+			recorder := httptest.NewRecorder()
+			handler(recorder, request)
+
+			requestID := recorder.Header().Get(RequestIDHeader)
+			if requestID == "" || (requestID == tt.requestID) != tt.wantRequestID {
+				t.Errorf("%s = %q, want the given %q: %v", RequestIDHeader, requestID, tt.requestID, tt.wantRequestID)
+			}
+			if recorder.Code != http.StatusCreated || recorder.Header().Get("Content-Type") != "application/json" {
+				t.Errorf("response %d with Content-Type %q, want %d with application/json", recorder.Code, recorder.Header().Get("Content-Type"), http.StatusCreated)
+			}
+
+			var entries []map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+				var entry map[string]any
+				if err := json.Unmarshal([]byte(line), &entry); err != nil {
+					t.Fatal(err)
+				}
+				if entry["request_id"] != requestID {
+					t.Errorf("log %q has request_id %v, want %q", entry["message"], entry["request_id"], requestID)
+				}
+				entries = append(entries, entry)
+			}
+			if len(entries) != 3 || entries[1]["message"] != "tasks added" || entries[1]["caller"] != "planner" {
+				t.Fatalf("logs = %v, want the start, the controller log with the caller and the completion", entries)
+			}
+			completed := entries[2]
+			if completed["status"] != float64(http.StatusCreated) ||
+				completed["request_size"] != float64(len(`[{}, {}]`)) ||
+				completed["response_size"] != float64(recorder.Body.Len()) ||
+				completed["duration"] == nil || completed["caller"] != "planner" {
+				t.Errorf("completion log = %v", completed)
+			}
+		})
+	}
+}
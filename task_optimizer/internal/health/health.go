@@ -0,0 +1,98 @@
+// Package health reports whether the service is ready to serve requests.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// StartupCheck is the name of the check of the startup of the service.
+const StartupCheck = "startup"
+
+// checkTimeout bounds the time each check takes.
+const checkTimeout = 2 * time.Second
+
+var ErrStarting = errors.New("startup in progress")
+
+// Check reports whether a dependency of the service is available.
+type Check func(ctx context.Context) error
+
+// Result is the result of a check, with a nil Err if it passed.
+type Result struct {
+	Name string
+	Err  error
+}
+
+// Checker checks the readiness of the service: the service is ready once
+// its startup is done, while all the checks pass.
+type Checker struct {
+	started atomic.Bool
+
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]Check
+}
+
+func NewChecker() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// AddCheck adds the check named name, replacing the check with the same
+// name if any.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = check
+}
+
+// SetStarted marks the startup of the service, including the recovery of
+// its state, as done.
+func (c *Checker) SetStarted() {
+	c.started.Store(true)
+}
+
+// Check runs the checks concurrently and returns their results, preceded by
+// the result of StartupCheck, in the order they were added.
+func (c *Checker) Check(ctx context.Context) []Result {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = c.checks[name]
+	}
+	c.mu.RUnlock()
+
+	results := make([]Result, len(names)+1)
+	results[0] = Result{Name: StartupCheck}
+	if !c.started.Load() {
+		results[0].Err = ErrStarting
+	}
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i+1] = Result{Name: names[i], Err: check(ctx)}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Ready reports whether all the results passed.
+func Ready(results []Result) bool {
+	for _, result := range results {
+		if result.Err != nil {
+			return false
+		}
+	}
+	return true
+}
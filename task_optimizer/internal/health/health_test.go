@@ -0,0 +1,39 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker()
+	errUnreachable := errors.New("unreachable")
+	storageErr := errUnreachable
+	checker.AddCheck("storage", func(ctx context.Context) error { return storageErr })
+	checker.AddCheck("catalog", func(ctx context.Context) error { return nil })
+
+	tests := []struct {
+		name      string
+		prepare   func()
+		want      []Result
+		wantReady bool
+	}{
+		{"starting", func() {}, []Result{{StartupCheck, ErrStarting}, {"storage", errUnreachable}, {"catalog", nil}}, false},
+		{"check failing", checker.SetStarted, []Result{{StartupCheck, nil}, {"storage", errUnreachable}, {"catalog", nil}}, false},
+		{"ready", func() { storageErr = nil }, []Result{{StartupCheck, nil}, {"storage", nil}, {"catalog", nil}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.prepare()
+			got := checker.Check(context.Background())
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+			if ready := Ready(got); ready != tt.wantReady {
+				t.Errorf("Ready() = %v, want %v", ready, tt.wantReady)
+			}
+		})
+	}
+}
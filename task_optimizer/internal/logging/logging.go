@@ -0,0 +1,45 @@
+// Package logging carries the logger of each request in its context, so that
+// everything logged while serving the request, by the transport, the
+// controllers or the service, is tagged with its request ID.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"regexp"
+)
+
+type contextKey struct{}
+
+// requestIDPattern accepts the request IDs given by clients that are safe to
+// log and echo back, such as UUIDs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// WithLogger returns a copy of ctx holding logger.
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the request of ctx, or a copy of the
+// global logger if there is none, so that updating its context never
+// changes the global logger.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*zerolog.Logger); ok {
+		return logger
+	}
+	logger := log.Logger
+	return &logger
+}
+
+// RequestID returns id if it's a valid request ID, or else a new random one.
+func RequestID(id string) string {
+	if requestIDPattern.MatchString(id) {
+		return id
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	if got := RequestID("0b6f1c9e-7f5d-4b7e-9a53-2c1f0d8e6a41"); got != "0b6f1c9e-7f5d-4b7e-9a53-2c1f0d8e6a41" {
+		t.Errorf("RequestID() of a valid ID = %q", got)
+	}
+	for _, id := range []string{"", "id\nforged log line", string(make([]byte, 129))} {
+		got := RequestID(id)
+		if got == id || !requestIDPattern.MatchString(got) {
+			t.Errorf("RequestID(%q) = %q, want a new ID", id, got)
+		}
+	}
+	if RequestID("") == RequestID("") {
+		t.Errorf("RequestID() generated the same ID twice")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	logger := zerolog.Nop()
+	if got := FromContext(WithLogger(context.Background(), &logger)); got != &logger {
+		t.Errorf("FromContext() didn't return the logger of the context")
+	}
+	if got := FromContext(context.Background()); got == &log.Logger {
+		t.Errorf("FromContext() without a logger returned the global logger")
+	}
+}
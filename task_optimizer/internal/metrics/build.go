@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"task_optimizer/internal/buildinfo"
+)
+
+type BuildMetrics struct {
+	// Info is always 1, labelled with the version of the service.
+	Info prometheus.Gauge
+}
+
+func NewBuildMetrics(info buildinfo.Info) *BuildMetrics {
+	metrics := &BuildMetrics{
+		Info: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "task_optimizer_build_info",
+			Help: "Version of the service, with the commit, build time and Go version it was built with",
+			ConstLabels: prometheus.Labels{
+				"commit":     info.Commit,
+				"build_time": info.BuildTime,
+				"go_version": info.GoVersion,
+			},
+		}),
+	}
+	metrics.Info.Set(1)
+
+	prometheus.MustRegister(
+		metrics.Info,
+	)
+
+	return metrics
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type HTTPMetrics struct {
+	RejectedRequests *prometheus.CounterVec
+}
+
+func NewHTTPMetrics() *HTTPMetrics {
+	metrics := &HTTPMetrics{
+		RejectedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_http_rejected_requests_total",
+			Help: "Number of HTTP requests rejected by the limits, by reason (rate_limit, body_size or task_count)",
+		}, []string{"reason"}),
+	}
+
+	prometheus.MustRegister(
+		metrics.RejectedRequests,
+	)
+
+	return metrics
+}
@@ -2,41 +2,164 @@ package metrics
 
 import (
 	"github.com/prometheus/client_golang/prometheus"
+	"sync"
 )
 
+const (
+	// MaxClients is the number of clients labelled by name in the selections
+	// of each namespace. Tasks name their client freely, so the clients seen
+	// after them are labelled OtherClient to keep the series bounded.
+	MaxClients  = 100
+	OtherClient = "other"
+)
+
+// TaskServiceMetrics are the metrics of the task service of a namespace.
 type TaskServiceMetrics struct {
-	ProcessingTime    prometheus.Summary
-	BronKerboschTime  prometheus.Summary
-	InputTaskListSize prometheus.Histogram
-	TaskListSize      prometheus.Gauge
+	ProcessingTime        prometheus.Observer
+	BronKerboschTime      prometheus.Observer
+	ConstrainedSearchTime prometheus.Observer
+	InputTaskListSize     prometheus.Observer
+	TaskListSize          prometheus.Gauge
+	FleetSize             prometheus.Gauge
+	EvictedTasks          prometheus.Counter
+	TemplateTasks         prometheus.Counter
+	SolverTimeouts        prometheus.Counter
+
+	ClientSelectedProfit *prometheus.CounterVec
+	ClientSelectedTasks  *prometheus.CounterVec
+
+	clientsMu sync.Mutex
+	clients   map[string]bool
+}
+
+// ClientLabel returns the label of client in the client metrics.
+func (m *TaskServiceMetrics) ClientLabel(client string) string {
+	m.clientsMu.Lock()
+	defer m.clientsMu.Unlock()
+	if m.clients == nil {
+		m.clients = map[string]bool{}
+	}
+	if !m.clients[client] {
+		if len(m.clients) >= MaxClients {
+			return OtherClient
+		}
+		m.clients[client] = true
+	}
+	return client
 }
 
-func NewTaskServiceMetrics() *TaskServiceMetrics {
-	metrics := &TaskServiceMetrics{
-		ProcessingTime: prometheus.NewSummary(prometheus.SummaryOpts{
+// TaskServiceMetricsVec holds the task service metrics of every namespace,
+// labelled by namespace.
+type TaskServiceMetricsVec struct {
+	ProcessingTime        *prometheus.SummaryVec
+	BronKerboschTime      *prometheus.SummaryVec
+	ConstrainedSearchTime *prometheus.SummaryVec
+	InputTaskListSize     *prometheus.HistogramVec
+	TaskListSize          *prometheus.GaugeVec
+	FleetSize             *prometheus.GaugeVec
+	EvictedTasks          *prometheus.CounterVec
+	TemplateTasks         *prometheus.CounterVec
+	SolverTimeouts        *prometheus.CounterVec
+
+	ClientSelectedProfit *prometheus.CounterVec
+	ClientSelectedTasks  *prometheus.CounterVec
+}
+
+func NewTaskServiceMetricsVec() *TaskServiceMetricsVec {
+	metrics := &TaskServiceMetricsVec{
+		ProcessingTime: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Name: "task_optimizer_processing_duration_seconds",
 			Help: "Time it takes to optimize for profit the list of tasks to execute in seconds",
-		}),
-		BronKerboschTime: prometheus.NewSummary(prometheus.SummaryOpts{
+		}, []string{"namespace"}),
+		BronKerboschTime: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Name: "task_optimizer_bron_kerbosch_duration_seconds",
 			Help: "Time it takes to run the BronKerbosch algorithm in the task compatibility graph",
-		}),
-		InputTaskListSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+		}, []string{"namespace"}),
+		ConstrainedSearchTime: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Name: "task_optimizer_constrained_search_duration_seconds",
+			Help: "Time it takes to search the task compatibility graph for the best clique within an energy budget or fairness policy",
+		}, []string{"namespace"}),
+		InputTaskListSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
 			Name: "task_optimizer_input_task_list_size",
 			Help: "Input size of the task list to optimize",
-		}),
-		TaskListSize: prometheus.NewGauge(prometheus.GaugeOpts{
+		}, []string{"namespace"}),
+		TaskListSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: "task_optimizer_task_list_size",
 			Help: "Task list size",
-		}),
+		}, []string{"namespace"}),
+		FleetSize: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "task_optimizer_fleet_size",
+			Help: "Number of satellites registered in the fleet",
+		}, []string{"namespace"}),
+		EvictedTasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_evicted_tasks_total",
+			Help: "Number of tasks evicted from the task list because they expired",
+		}, []string{"namespace"}),
+		TemplateTasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_template_tasks_total",
+			Help: "Number of tasks enqueued by recurring task templates",
+		}, []string{"namespace"}),
+		SolverTimeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_solver_timeouts_total",
+			Help: "Number of plans returned by the solver when it ran out of time, which may not be the best ones",
+		}, []string{"namespace"}),
+		ClientSelectedProfit: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_client_selected_profit_total",
+			Help: "Effective profit of the tasks selected for execution by client",
+		}, []string{"namespace", "client"}),
+		ClientSelectedTasks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_client_selected_tasks_total",
+			Help: "Number of tasks selected for execution by client",
+		}, []string{"namespace", "client"}),
 	}
 
 	prometheus.MustRegister(
 		metrics.ProcessingTime,
 		metrics.BronKerboschTime,
+		metrics.ConstrainedSearchTime,
 		metrics.InputTaskListSize,
 		metrics.TaskListSize,
+		metrics.FleetSize,
+		metrics.EvictedTasks,
+		metrics.TemplateTasks,
+		metrics.SolverTimeouts,
+		metrics.ClientSelectedProfit,
+		metrics.ClientSelectedTasks,
 	)
 
 	return metrics
 }
+
+// ForNamespace returns the metrics of the task service of the namespace.
+func (m *TaskServiceMetricsVec) ForNamespace(namespace string) *TaskServiceMetrics {
+	labels := prometheus.Labels{"namespace": namespace}
+	return &TaskServiceMetrics{
+		ProcessingTime:        m.ProcessingTime.With(labels),
+		BronKerboschTime:      m.BronKerboschTime.With(labels),
+		ConstrainedSearchTime: m.ConstrainedSearchTime.With(labels),
+		InputTaskListSize:     m.InputTaskListSize.With(labels),
+		TaskListSize:          m.TaskListSize.With(labels),
+		FleetSize:             m.FleetSize.With(labels),
+		EvictedTasks:          m.EvictedTasks.With(labels),
+		TemplateTasks:         m.TemplateTasks.With(labels),
+		SolverTimeouts:        m.SolverTimeouts.With(labels),
+		ClientSelectedProfit:  m.ClientSelectedProfit.MustCurryWith(labels),
+		ClientSelectedTasks:   m.ClientSelectedTasks.MustCurryWith(labels),
+	}
+}
+
+// DeleteNamespace removes the series of the namespace.
+func (m *TaskServiceMetricsVec) DeleteNamespace(namespace string) {
+	labels := prometheus.Labels{"namespace": namespace}
+	m.ProcessingTime.DeletePartialMatch(labels)
+	m.BronKerboschTime.DeletePartialMatch(labels)
+	m.ConstrainedSearchTime.DeletePartialMatch(labels)
+	m.InputTaskListSize.DeletePartialMatch(labels)
+	m.TaskListSize.DeletePartialMatch(labels)
+	m.FleetSize.DeletePartialMatch(labels)
+	m.EvictedTasks.DeletePartialMatch(labels)
+	m.TemplateTasks.DeletePartialMatch(labels)
+	m.SolverTimeouts.DeletePartialMatch(labels)
+	m.ClientSelectedProfit.DeletePartialMatch(labels)
+	m.ClientSelectedTasks.DeletePartialMatch(labels)
+}
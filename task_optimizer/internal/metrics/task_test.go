@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTaskServiceMetrics_ClientLabel(t *testing.T) {
+	m := &TaskServiceMetrics{}
+	for i := 0; i < MaxClients; i++ {
+		client := fmt.Sprintf("client-%d", i)
+		if got := m.ClientLabel(client); got != client {
+			t.Errorf("ClientLabel(%q) = %q, want %q", client, got, client)
+		}
+	}
+	if got := m.ClientLabel("late"); got != OtherClient {
+		t.Errorf("ClientLabel(%q) = %q, want %q", "late", got, OtherClient)
+	}
+	if got := m.ClientLabel("client-0"); got != "client-0" {
+		t.Errorf("ClientLabel(%q) = %q, want %q", "client-0", got, "client-0")
+	}
+}
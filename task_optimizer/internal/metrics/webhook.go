@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type WebhookMetrics struct {
+	DeliveryAttempts *prometheus.CounterVec
+	DeliveryTime     prometheus.Summary
+	DeadLetters      prometheus.Counter
+}
+
+func NewWebhookMetrics() *WebhookMetrics {
+	metrics := &WebhookMetrics{
+		DeliveryAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "task_optimizer_webhook_delivery_attempts_total",
+			Help: "Number of webhook delivery attempts by result",
+		}, []string{"result"}),
+		DeliveryTime: prometheus.NewSummary(prometheus.SummaryOpts{
+			Name: "task_optimizer_webhook_delivery_duration_seconds",
+			Help: "Time it takes a webhook delivery attempt to complete",
+		}),
+		DeadLetters: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "task_optimizer_webhook_dead_letters_total",
+			Help: "Number of webhook deliveries that failed after every attempt",
+		}),
+	}
+
+	prometheus.MustRegister(
+		metrics.DeliveryAttempts,
+		metrics.DeliveryTime,
+		metrics.DeadLetters,
+	)
+
+	return metrics
+}
@@ -0,0 +1,36 @@
+package model
+
+// ConflictModel holds the resources that tasks can share paying a profit
+// penalty for each pair of tasks sharing them. Resources not in the model
+// can't be shared.
+type ConflictModel map[string]float64
+
+// Conflict reports whether the tasks can be executed together under the
+// resource policy and the penalty paid for doing so. Only tasks claiming the
+// very same resource can share it, overlaps through the hierarchy of the
+// catalog are always conflicts.
+func (task Task) Conflict(other Task, policy ResourcePolicy) (bool, float64) {
+	var penalty float64
+	for resource := range task.Resources {
+		for otherResource := range other.Resources {
+			if !policy.Catalog.Overlap(resource, otherResource) {
+				continue
+			}
+			resourcePenalty, shareable := policy.Conflicts[resource]
+			if resource != otherResource || !shareable {
+				return false, 0
+			}
+			penalty += resourcePenalty
+		}
+	}
+
+	return true, penalty
+}
+
+// Penalty is the profit penalty paid for executing two tasks that share
+// resources.
+type Penalty struct {
+	Tasks     [2]Task
+	Resources []string
+	Amount    float64
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type DecayKind int
+
+const (
+	DecayNone DecayKind = iota
+	// DecayLinear loses Rate profit for every hour waited, down to zero.
+	DecayLinear
+	// DecayExponential halves the profit every HalfLife.
+	DecayExponential
+	// DecayStep multiplies the profit by Factor once After has elapsed.
+	DecayStep
+)
+
+var decayKindNames = map[DecayKind]string{
+	DecayNone:        "none",
+	DecayLinear:      "linear",
+	DecayExponential: "exponential",
+	DecayStep:        "step",
+}
+
+func (k DecayKind) String() string {
+	if name, ok := decayKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("DecayKind(%d)", int(k))
+}
+
+func ParseDecayKind(name string) (DecayKind, error) {
+	for kind, kindName := range decayKindNames {
+		if kindName == name {
+			return kind, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown decay %q", name)
+}
+
+// Decay describes how the profit of a task drops while it waits, relative to
+// its submission time.
+type Decay struct {
+	Kind     DecayKind
+	Rate     float64
+	HalfLife time.Duration
+	After    time.Duration
+	Factor   float64
+}
+
+func (d Decay) Validate() error {
+	switch d.Kind {
+	case DecayNone:
+	case DecayLinear:
+		if d.Rate < 0 {
+			return fmt.Errorf("linear decay rate must not be negative")
+		}
+	case DecayExponential:
+		if d.HalfLife <= 0 {
+			return fmt.Errorf("exponential decay half life must be positive")
+		}
+	case DecayStep:
+		if d.After < 0 || d.Factor < 0 || d.Factor > 1 {
+			return fmt.Errorf("step decay must have a non negative delay and a factor between 0 and 1")
+		}
+	default:
+		return fmt.Errorf("unknown decay %v", d.Kind)
+	}
+	return nil
+}
+
+// Apply returns the value of profit after waiting for elapsed.
+func (d Decay) Apply(profit float64, elapsed time.Duration) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	switch d.Kind {
+	case DecayLinear:
+		decayed := profit - d.Rate*elapsed.Hours()
+		if profit > 0 && decayed < 0 {
+			return 0
+		}
+		return decayed
+	case DecayExponential:
+		return profit * math.Exp2(-float64(elapsed)/float64(d.HalfLife))
+	case DecayStep:
+		if elapsed >= d.After {
+			return profit * d.Factor
+		}
+	}
+	return profit
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecay_Apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		decay   Decay
+		elapsed time.Duration
+		want    float64
+	}{
+		{"no decay", Decay{}, time.Hour, 10},
+		{"linear", Decay{Kind: DecayLinear, Rate: 2}, 2 * time.Hour, 6},
+		{"linear down to zero", Decay{Kind: DecayLinear, Rate: 2}, 10 * time.Hour, 0},
+		{"exponential at half life", Decay{Kind: DecayExponential, HalfLife: time.Hour}, time.Hour, 5},
+		{"exponential at two half lives", Decay{Kind: DecayExponential, HalfLife: time.Hour}, 2 * time.Hour, 2.5},
+		{"step before delay", Decay{Kind: DecayStep, After: time.Hour, Factor: 0.2}, time.Minute, 10},
+		{"step after delay", Decay{Kind: DecayStep, After: time.Hour, Factor: 0.2}, time.Hour, 2},
+		{"negative elapsed", Decay{Kind: DecayLinear, Rate: 2}, -time.Hour, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.decay.Apply(10, tt.elapsed); got != tt.want {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
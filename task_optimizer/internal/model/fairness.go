@@ -0,0 +1,86 @@
+package model
+
+import "fmt"
+
+type FairnessPolicy int
+
+const (
+	FairnessNone FairnessPolicy = iota
+	// FairnessMinShare requires every client with pending tasks to get at
+	// least MinShare of the profit of the plan.
+	FairnessMinShare
+	// FairnessCap limits the profit of each client in the plan to
+	// MaxClientProfit.
+	FairnessCap
+	// FairnessProportional maximizes the sum of the logarithm of the profit
+	// of each client, weighted by ClientWeights (1 by default).
+	FairnessProportional
+)
+
+var fairnessPolicyNames = map[FairnessPolicy]string{
+	FairnessNone:         "none",
+	FairnessMinShare:     "min-share",
+	FairnessCap:          "cap",
+	FairnessProportional: "proportional",
+}
+
+func (p FairnessPolicy) String() string {
+	if name, ok := fairnessPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("FairnessPolicy(%d)", int(p))
+}
+
+func ParseFairnessPolicy(name string) (FairnessPolicy, error) {
+	for policy, policyName := range fairnessPolicyNames {
+		if policyName == name {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown fairness policy %q", name)
+}
+
+// Fairness describes how the profit of a plan is shared among clients.
+type Fairness struct {
+	Policy          FairnessPolicy
+	MinShare        float64
+	MaxClientProfit float64
+	ClientWeights   map[string]float64
+}
+
+func (f Fairness) Validate() error {
+	switch f.Policy {
+	case FairnessNone:
+	case FairnessMinShare:
+		if f.MinShare < 0 || f.MinShare > 1 {
+			return fmt.Errorf("minimum client share must be between 0 and 1")
+		}
+	case FairnessCap:
+		if f.MaxClientProfit < 0 {
+			return fmt.Errorf("maximum client profit must not be negative")
+		}
+	case FairnessProportional:
+		for client, weight := range f.ClientWeights {
+			if weight <= 0 {
+				return fmt.Errorf("weight of client %q must be positive", client)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown fairness policy %v", f.Policy)
+	}
+	return nil
+}
+
+func (f Fairness) ClientWeight(client string) float64 {
+	if weight, ok := f.ClientWeights[client]; ok {
+		return weight
+	}
+	return 1
+}
+
+// ClientShare is the profit and number of tasks of a client in a plan.
+type ClientShare struct {
+	Client string
+	Profit float64
+	Tasks  int
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// namespacePattern accepts lowercase DNS labels, so that names are safe in
+// paths, metric labels and gRPC metadata.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// Namespace is an isolated queue of tasks, with its own resources,
+// satellites, outages, templates and webhooks, so that the tasks of the
+// teams sharing the service don't compete with each other.
+type Namespace struct {
+	Name      string
+	CreatedAt time.Time
+}
+
+func (n Namespace) Validate() error {
+	if !namespacePattern.MatchString(n.Name) {
+		return fmt.Errorf("namespace name %q must be a lowercase DNS label", n.Name)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Outage is a window of time in which a resource is unavailable, such as a
+// maintenance blackout. It starts at Start and ends at End, exclusive.
+type Outage struct {
+	ID       uint64
+	Resource string
+	Start    time.Time
+	End      time.Time
+	Reason   string
+}
+
+func (o Outage) Validate() error {
+	if o.Resource == "" {
+		return errors.New("outage without resource")
+	}
+	if !o.End.After(o.Start) {
+		return errors.New("outage must end after it starts")
+	}
+	return nil
+}
+
+func (o Outage) IsActive(at time.Time) bool {
+	return !at.Before(o.Start) && at.Before(o.End)
+}
+
+// Affects reports whether the task needs the resource of the outage, that is
+// it claims the resource itself, any of its ancestors or any of its
+// descendants in the catalog.
+func (o Outage) Affects(task Task, catalog ResourceCatalog) bool {
+	for resource := range task.Resources {
+		if resource == o.Resource || catalog.IsAncestor(resource, o.Resource) || catalog.IsAncestor(o.Resource, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deferral is a task left out of a plan because it needs a resource
+// unavailable due to the Outage.
+type Deferral struct {
+	Task   Task
+	Outage Outage
+}
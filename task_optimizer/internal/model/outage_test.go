@@ -0,0 +1,57 @@
+package model
+
+import (
+	"task_optimizer/internal/ds/set"
+	"testing"
+	"time"
+)
+
+func TestOutage_Affects(t *testing.T) {
+	catalog := ResourceCatalog{
+		"camera":         {Name: "camera"},
+		"camera.optical": {Name: "camera.optical"},
+		"camera.sar":     {Name: "camera.sar"},
+	}
+	tests := []struct {
+		name      string
+		outage    string
+		resources []string
+		want      bool
+	}{
+		{"same resource", "disk", []string{"proc", "disk"}, true},
+		{"other resource", "disk", []string{"proc"}, false},
+		{"child of the resource", "camera", []string{"camera.sar"}, true},
+		{"parent of the resource", "camera.sar", []string{"camera"}, true},
+		{"sibling of the resource", "camera.sar", []string{"camera.optical"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outage := Outage{Resource: tt.outage}
+			if got := outage.Affects(Task{Resources: set.Of(tt.resources...)}, catalog); got != tt.want {
+				t.Errorf("Affects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutage_IsActive(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	outage := Outage{Resource: "camera", Start: start, End: start.Add(time.Hour)}
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"before", start.Add(-time.Minute), false},
+		{"at start", start, true},
+		{"during", start.Add(30 * time.Minute), true},
+		{"at end", start.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outage.IsActive(tt.at); got != tt.want {
+				t.Errorf("IsActive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// PlanRequest holds the constraints of an execution. EnergyBudget is the
+// energy available for the whole plan, +Inf when there is no budget.
+// IfVersion is the version the task list must be at for the execution to
+// run, 0 for any version.
+type PlanRequest struct {
+	EnergyBudget float64
+	Fairness     Fairness
+	IfVersion    uint64
+}
+
+func UnconstrainedPlanRequest() PlanRequest {
+	return PlanRequest{
+		EnergyBudget: math.Inf(1),
+	}
+}
+
+// Plan is the set of tasks selected for execution at PlannedAt and the energy
+// they draw. Version is the version of the task list without them.
+type Plan struct {
+	PlannedAt    time.Time
+	Version      uint64
+	Assignments  []Assignment
+	EnergyBudget float64
+	EnergyUsed   float64
+	Fairness     Fairness
+	// FairnessMet is false when no plan satisfied the fairness policy and the
+	// plan was computed without it.
+	FairnessMet bool
+	Penalties   []Penalty
+	// Deferred holds the tasks left out of the plan because of outages.
+	Deferred []Deferral
+}
+
+func (plan Plan) PenaltyTotal() float64 {
+	var total float64
+	for _, penalty := range plan.Penalties {
+		total += penalty.Amount
+	}
+	return total
+}
+
+func (plan Plan) HasEnergyBudget() bool {
+	return !math.IsInf(plan.EnergyBudget, 1)
+}
+
+func (plan Plan) EnergyMargin() float64 {
+	return plan.EnergyBudget - plan.EnergyUsed
+}
+
+// ClientShares returns the effective profit and number of tasks of each
+// client in the plan, sorted by client.
+func (plan Plan) ClientShares() []ClientShare {
+	sharesByClient := make(map[string]*ClientShare)
+	for _, assignment := range plan.Assignments {
+		client := assignment.Task.Client
+		if _, ok := sharesByClient[client]; !ok {
+			sharesByClient[client] = &ClientShare{Client: client}
+		}
+		sharesByClient[client].Profit += assignment.Task.EffectiveProfit(plan.PlannedAt)
+		sharesByClient[client].Tasks++
+	}
+	shares := make([]ClientShare, 0, len(sharesByClient))
+	for _, share := range sharesByClient {
+		shares = append(shares, *share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].Client < shares[j].Client
+	})
+	return shares
+}
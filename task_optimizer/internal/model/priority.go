@@ -0,0 +1,42 @@
+package model
+
+import "fmt"
+
+// Priority is the SLA class of a task.
+type Priority int
+
+const (
+	// PriorityStandard is the zero value, so tasks are standard unless they
+	// say otherwise.
+	PriorityStandard Priority = iota
+	PriorityBestEffort
+	PriorityCritical
+)
+
+var priorityNames = map[Priority]string{
+	PriorityBestEffort: "best-effort",
+	PriorityStandard:   "standard",
+	PriorityCritical:   "critical",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// Valid reports whether p is one of the priority classes.
+func (p Priority) Valid() bool {
+	_, ok := priorityNames[p]
+	return ok
+}
+
+func ParsePriority(name string) (Priority, error) {
+	for priority, priorityName := range priorityNames {
+		if priorityName == name {
+			return priority, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q", name)
+}
@@ -0,0 +1,167 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TaskStatus is the state of a task waiting in the list.
+type TaskStatus int
+
+const (
+	TaskPending TaskStatus = iota
+	TaskPinned
+	TaskHeld
+	// TaskExpired tasks are waiting to be evicted.
+	TaskExpired
+)
+
+var taskStatusNames = map[TaskStatus]string{
+	TaskPending: "pending",
+	TaskPinned:  "pinned",
+	TaskHeld:    "held",
+	TaskExpired: "expired",
+}
+
+func (s TaskStatus) String() string {
+	if name, ok := taskStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("TaskStatus(%d)", int(s))
+}
+
+func ParseTaskStatus(name string) (TaskStatus, error) {
+	for status, statusName := range taskStatusNames {
+		if statusName == name {
+			return status, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task status %q", name)
+}
+
+func (task Task) Status(now time.Time) TaskStatus {
+	switch {
+	case task.IsExpired(now):
+		return TaskExpired
+	case task.Held:
+		return TaskHeld
+	case task.Pinned:
+		return TaskPinned
+	default:
+		return TaskPending
+	}
+}
+
+// TaskSort is the field tasks are sorted by, ties are sorted by ID.
+type TaskSort int
+
+const (
+	TaskSortID TaskSort = iota
+	TaskSortProfit
+	TaskSortSubmittedAt
+)
+
+var taskSortNames = map[TaskSort]string{
+	TaskSortID:          "id",
+	TaskSortProfit:      "profit",
+	TaskSortSubmittedAt: "submittedAt",
+}
+
+func (s TaskSort) String() string {
+	if name, ok := taskSortNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("TaskSort(%d)", int(s))
+}
+
+func ParseTaskSort(name string) (TaskSort, error) {
+	for sort, sortName := range taskSortNames {
+		if sortName == name {
+			return sort, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown task sort %q", name)
+}
+
+// TaskQuery selects a page of the tasks in the list. Empty filters match
+// every task.
+type TaskQuery struct {
+	// Resources holds resources that the tasks must all claim.
+	Resources []string
+	// Name is a case insensitive substring of the name of the tasks.
+	Name string
+	// MinProfit and MaxProfit bound the nominal profit of the tasks, they
+	// are -Inf and +Inf when there is no bound.
+	MinProfit float64
+	MaxProfit float64
+	Clients   []string
+	Statuses  []TaskStatus
+
+	Sort       TaskSort
+	Descending bool
+	// Cursor is the position after which the page starts, returned as the
+	// NextCursor of the previous page, "" for the first page.
+	Cursor string
+	Limit  int
+}
+
+func AllTasksQuery() TaskQuery {
+	return TaskQuery{
+		MinProfit: math.Inf(-1),
+		MaxProfit: math.Inf(1),
+	}
+}
+
+// Matches reports whether the task at now passes the filters of the query.
+func (q TaskQuery) Matches(task Task, now time.Time) bool {
+	for _, resource := range q.Resources {
+		if !task.Resources.Contains(resource) {
+			return false
+		}
+	}
+	if q.Name != "" && !strings.Contains(strings.ToLower(task.Name), strings.ToLower(q.Name)) {
+		return false
+	}
+	if task.Profit < q.MinProfit || task.Profit > q.MaxProfit {
+		return false
+	}
+	if len(q.Clients) > 0 && !slices.Contains(q.Clients, task.Client) {
+		return false
+	}
+	return len(q.Statuses) == 0 || slices.Contains(q.Statuses, task.Status(now))
+}
+
+// Compare returns a negative number if task goes before other in the order
+// of the query, a positive number if it goes after and 0 if they are the
+// same task.
+func (q TaskQuery) Compare(task, other Task) int {
+	var c int
+	switch q.Sort {
+	case TaskSortProfit:
+		c = cmp.Compare(task.Profit, other.Profit)
+	case TaskSortSubmittedAt:
+		c = task.SubmittedAt.Compare(other.SubmittedAt)
+	}
+	if c == 0 {
+		c = cmp.Compare(task.ID, other.ID)
+	}
+	if q.Descending {
+		return -c
+	}
+	return c
+}
+
+// TaskPage is a page of the tasks matching a query. Total is the number of
+// tasks matching the query in every page, NextCursor is the cursor of the
+// next page, "" for the last page, and Version the version of the task list
+// the page was read at.
+type TaskPage struct {
+	Tasks      []Task
+	Total      int
+	NextCursor string
+	Version    uint64
+}
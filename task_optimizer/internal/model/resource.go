@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource is an entry of the resource catalog. Child resources are named
+// after their parent followed by a dot, e.g. "camera.optical" is a child of
+// "camera".
+type Resource struct {
+	Name string
+	// ExclusiveChildren makes the children of the resource conflict with
+	// each other. Otherwise children only conflict with their ancestors.
+	ExclusiveChildren bool
+}
+
+// Parent returns the name of the parent of the resource, or "" if it's a
+// root resource.
+func (resource Resource) Parent() string {
+	if i := strings.LastIndex(resource.Name, "."); i >= 0 {
+		return resource.Name[:i]
+	}
+	return ""
+}
+
+// ResourceCatalog holds the known resources by name. An empty catalog has no
+// hierarchy and accepts any resource name.
+type ResourceCatalog map[string]Resource
+
+// Validate returns an error if the resource is not in the catalog. Every
+// resource is valid for an empty catalog.
+func (c ResourceCatalog) Validate(name string) error {
+	if len(c) == 0 {
+		return nil
+	}
+	if _, ok := c[name]; !ok {
+		return fmt.Errorf("unknown resource %q", name)
+	}
+	return nil
+}
+
+// IsAncestor reports whether ancestor is a strict ancestor of resource in
+// the catalog.
+func (c ResourceCatalog) IsAncestor(ancestor, resource string) bool {
+	entry, ok := c[resource]
+	for ok && entry.Parent() != "" {
+		if entry.Parent() == ancestor {
+			return true
+		}
+		entry, ok = c[entry.Parent()]
+	}
+	return false
+}
+
+// Overlap reports whether claiming both resources is a conflict: the same
+// resource, a resource and any of its descendants, or siblings whose parent
+// has exclusive children.
+func (c ResourceCatalog) Overlap(resource, other string) bool {
+	if resource == other || c.IsAncestor(resource, other) || c.IsAncestor(other, resource) {
+		return true
+	}
+	entry, ok := c[resource]
+	if !ok || entry.Parent() == "" || entry.Parent() != c[other].Parent() {
+		return false
+	}
+	return c[entry.Parent()].ExclusiveChildren
+}
+
+// ResourcePolicy describes how tasks claiming resources interact: the
+// hierarchy of the catalog and the resources that can be shared.
+type ResourcePolicy struct {
+	Catalog   ResourceCatalog
+	Conflicts ConflictModel
+}
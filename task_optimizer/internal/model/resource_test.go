@@ -0,0 +1,40 @@
+package model
+
+import (
+	"task_optimizer/internal/ds/set"
+	"testing"
+)
+
+func TestTask_IsCompatible_Hierarchy(t *testing.T) {
+	catalog := ResourceCatalog{
+		"camera":         {Name: "camera"},
+		"camera.optical": {Name: "camera.optical"},
+		"camera.sar":     {Name: "camera.sar"},
+		"antenna":        {Name: "antenna", ExclusiveChildren: true},
+		"antenna.x":      {Name: "antenna.x"},
+		"antenna.s":      {Name: "antenna.s"},
+	}
+	tests := []struct {
+		name            string
+		resource, other string
+		catalog         ResourceCatalog
+		want            bool
+	}{
+		{"same resource", "camera.sar", "camera.sar", catalog, false},
+		{"parent and child", "camera", "camera.optical", catalog, false},
+		{"child and parent", "camera.sar", "camera", catalog, false},
+		{"siblings", "camera.optical", "camera.sar", catalog, true},
+		{"exclusive siblings", "antenna.x", "antenna.s", catalog, false},
+		{"unrelated", "camera", "antenna.x", catalog, true},
+		{"no catalog", "camera", "camera.optical", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{Resources: set.Of(tt.resource)}
+			other := Task{Resources: set.Of(tt.other)}
+			if got := task.IsCompatible(other, tt.catalog); got != tt.want {
+				t.Errorf("IsCompatible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import "task_optimizer/internal/ds/set"
+
+type Satellite struct {
+	Name      string
+	Resources set.Set[string]
+}
+
+// CanServe reports whether the satellite is able to execute the task. If the
+// task names the satellites that can serve it only those are considered,
+// otherwise the satellite must have every resource the task needs.
+func (satellite Satellite) CanServe(task Task) bool {
+	if len(task.Satellites) > 0 {
+		return task.Satellites.Contains(satellite.Name)
+	}
+	for resource := range task.Resources {
+		if !satellite.Resources.Contains(resource) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Assignment is a task scheduled for execution on a satellite. Satellite is
+// empty when the service runs without a fleet (single platform).
+type Assignment struct {
+	Task      Task
+	Satellite string
+}
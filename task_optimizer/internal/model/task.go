@@ -1,19 +1,50 @@
 package model
 
-import "task_optimizer/internal/ds/set"
+import (
+	"task_optimizer/internal/ds/set"
+	"time"
+)
 
 type Task struct {
-	Name      string
-	Resources set.Set[string]
-	Profit    float64
+	// ID is set by the service when the task is added.
+	ID         uint64
+	Name       string
+	Client     string
+	Resources  set.Set[string]
+	Profit     float64
+	Satellites set.Set[string]
+	Energy     float64
+	Priority   Priority
+	// SubmittedAt is set by the service when the task is added.
+	SubmittedAt time.Time
+	// ExpiresAt is the end of the acquisition window of the task, the task
+	// never expires if it's the zero time.
+	ExpiresAt time.Time
+	Decay     Decay
+	// Pinned tasks are forced into the next execution and held tasks are
+	// kept out of executions until released.
+	Pinned bool
+	Held   bool
+	// TemplateID is the template that enqueued the task, 0 if none did.
+	TemplateID uint64
+	// SubmittedBy is the name of the API key that added the task, or its
+	// template, empty when authentication is disabled.
+	SubmittedBy string
 }
 
-func (task Task) IsCompatible(other Task) bool {
-	for resource := range task.Resources {
-		if other.Resources.Contains(resource) {
-			return false
-		}
-	}
+// EffectiveProfit returns the profit of the task at now, once decayed since
+// its submission.
+func (task Task) EffectiveProfit(now time.Time) float64 {
+	return task.Decay.Apply(task.Profit, now.Sub(task.SubmittedAt))
+}
+
+func (task Task) IsExpired(now time.Time) bool {
+	return !task.ExpiresAt.IsZero() && !now.Before(task.ExpiresAt)
+}
 
-	return true
+// IsCompatible reports whether the tasks don't claim overlapping resources in
+// the hierarchy of the catalog.
+func (task Task) IsCompatible(other Task, catalog ResourceCatalog) bool {
+	compatible, _ := task.Conflict(other, ResourcePolicy{Catalog: catalog})
+	return compatible
 }
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"task_optimizer/internal/schedule"
+	"time"
+)
+
+// TaskTemplate enqueues a new instance of Task on every activation of its
+// schedule, defined either by Interval or by the Cron expression.
+type TaskTemplate struct {
+	ID       uint64
+	Name     string
+	Task     Task
+	Interval time.Duration
+	Cron     string
+	Schedule schedule.Schedule
+	// ExpiresAfter is the acquisition window of each instance, instances
+	// never expire if it's zero.
+	ExpiresAfter time.Duration
+	NextRun      time.Time
+}
+
+// NewInstance returns the task to enqueue for the template at now. The
+// instance keeps the pinned and held flags of Task on purpose, so that a
+// template can enqueue a housekeeping task that every execution includes, or
+// one that waits for review.
+func (template TaskTemplate) NewInstance(now time.Time) Task {
+	task := template.Task
+	task.TemplateID = template.ID
+	task.ExpiresAt = time.Time{}
+	if template.ExpiresAfter > 0 {
+		task.ExpiresAt = now.Add(template.ExpiresAfter)
+	}
+	return task
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+// Webhook is a subscription to the executions. The selected tasks are posted
+// to URL, signed with Secret.
+type Webhook struct {
+	ID     uint64
+	URL    string
+	Secret string
+}
+
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if w.Secret == "" {
+		return errors.New("webhook without secret")
+	}
+	return nil
+}
+
+// DeadLetter is a webhook delivery that failed after every attempt.
+type DeadLetter struct {
+	ID        uint64
+	WebhookID uint64
+	URL       string
+	EventID   uint64
+	Payload   []byte
+	Attempts  int
+	Error     string
+	FailedAt  time.Time
+}
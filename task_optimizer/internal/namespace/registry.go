@@ -0,0 +1,173 @@
+// Package namespace isolates the teams sharing the service in namespaces,
+// each with its own task service: its own queue, lock, resource catalog and
+// metrics labels.
+package namespace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+	"time"
+)
+
+// Default is the namespace served at the root of the APIs. It always exists.
+const Default = "default"
+
+var (
+	ErrNamespaceNotFound    = errors.New("namespace not found")
+	ErrNamespaceExists      = errors.New("namespace already exists")
+	ErrInvalidNamespaceName = errors.New("invalid namespace name")
+	ErrDefaultNamespace     = errors.New("the default namespace can't be deleted")
+)
+
+// Runner runs the background work of the task service of a namespace, such
+// as its reaper, scheduler and webhook dispatcher, until ctx is done.
+type Runner func(ctx context.Context, taskService *service.TaskService)
+
+// Namespace is a namespace and its task service.
+type Namespace struct {
+	model.Namespace
+	Service *service.TaskService
+
+	cancel context.CancelFunc
+
+	handlerOnce sync.Once
+	handler     http.Handler
+}
+
+// Handler returns the HTTP handler of the namespace, built with build the
+// first time.
+func (n *Namespace) Handler(build func(taskService *service.TaskService) http.Handler) http.Handler {
+	n.handlerOnce.Do(func() {
+		n.handler = build(n.Service)
+	})
+	return n.handler
+}
+
+// Registry holds the namespaces of the service.
+type Registry struct {
+	ctx     context.Context
+	config  service.Config
+	metrics *metrics.TaskServiceMetricsVec
+	run     Runner
+
+	mu         sync.RWMutex
+	namespaces map[string]*Namespace
+	// runners tracks the runners of the namespaces until they return.
+	runners sync.WaitGroup
+}
+
+// NewRegistry returns a registry with the default namespace. The task service
+// of each namespace is created with config and run with run, if not nil,
+// until the namespace is deleted or ctx is done.
+func NewRegistry(ctx context.Context, config service.Config, taskServiceMetrics *metrics.TaskServiceMetricsVec, run Runner) *Registry {
+	r := &Registry{
+		ctx:        ctx,
+		config:     config,
+		metrics:    taskServiceMetrics,
+		run:        run,
+		namespaces: make(map[string]*Namespace),
+	}
+	r.namespaces[Default] = r.newNamespace(model.Namespace{Name: Default, CreatedAt: time.Now()})
+	return r
+}
+
+func (r *Registry) newNamespace(info model.Namespace) *Namespace {
+	ctx, cancel := context.WithCancel(r.ctx)
+	namespace := &Namespace{
+		Namespace: info,
+		Service:   service.NewTaskService(r.config, r.metrics.ForNamespace(info.Name)),
+		cancel:    cancel,
+	}
+	if r.run != nil {
+		r.runners.Add(1)
+		go func() {
+			defer r.runners.Done()
+			r.run(ctx, namespace.Service)
+		}()
+	}
+	return namespace
+}
+
+// Wait waits for the runners of the namespaces to return once the context of
+// the registry is done, so that their work in flight completes. It returns
+// ctx.Err() if ctx is done first.
+func (r *Registry) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.runners.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Get returns the namespace named name.
+func (r *Registry) Get(name string) (*Namespace, error) {
+	r.mu.RLock()
+	namespace, ok := r.namespaces[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+	}
+	return namespace, nil
+}
+
+// List returns the namespaces sorted by name.
+func (r *Registry) List() []model.Namespace {
+	r.mu.RLock()
+	namespaces := make([]model.Namespace, 0, len(r.namespaces))
+	for _, namespace := range r.namespaces {
+		namespaces = append(namespaces, namespace.Namespace)
+	}
+	r.mu.RUnlock()
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces
+}
+
+// Create creates an empty namespace named name and starts its task service.
+func (r *Registry) Create(name string) (model.Namespace, error) {
+	info := model.Namespace{Name: name, CreatedAt: time.Now()}
+	if err := info.Validate(); err != nil {
+		return model.Namespace{}, fmt.Errorf("%w: %v", ErrInvalidNamespaceName, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.namespaces[name]; ok {
+		return model.Namespace{}, fmt.Errorf("%w: %s", ErrNamespaceExists, name)
+	}
+	r.namespaces[name] = r.newNamespace(info)
+	return info, nil
+}
+
+// Delete deletes the namespace named name with its tasks, stops its task
+// service, ends its event streams and removes its metrics. Requests in flight
+// in the namespace complete, but new requests don't find it.
+func (r *Registry) Delete(name string) error {
+	if name == Default {
+		return ErrDefaultNamespace
+	}
+	r.mu.Lock()
+	namespace, ok := r.namespaces[name]
+	delete(r.namespaces, name)
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+	}
+	namespace.cancel()
+	namespace.Service.Close()
+	r.metrics.DeleteNamespace(name)
+	return nil
+}
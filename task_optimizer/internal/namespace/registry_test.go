@@ -0,0 +1,112 @@
+package namespace
+
+import (
+	"context"
+	"errors"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+	"testing"
+	"time"
+)
+
+var taskServiceMetrics = metrics.NewTaskServiceMetricsVec()
+
+func TestRegistry(t *testing.T) {
+	stopped := make(chan string, 1)
+	registry := NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, func(ctx context.Context, taskService *service.TaskService) {
+		<-ctx.Done()
+		stopped <- "stopped"
+	})
+
+	if _, err := registry.Create("mission-a"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"duplicated", func() error { _, err := registry.Create("mission-a"); return err }, ErrNamespaceExists},
+		{"uppercase name", func() error { _, err := registry.Create("Mission"); return err }, ErrInvalidNamespaceName},
+		{"path in name", func() error { _, err := registry.Create("a/b"); return err }, ErrInvalidNamespaceName},
+		{"delete default", func() error { return registry.Delete(Default) }, ErrDefaultNamespace},
+		{"delete unknown", func() error { return registry.Delete("mission-b") }, ErrNamespaceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	missionA, err := registry.Get("mission-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultNamespace, err := registry.Get(Default)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := missionA.Service.AddTasks([]model.Task{{Name: "capture", Resources: set.Of("camera"), Profit: 1}}, service.AnyVersion); err != nil {
+		t.Fatal(err)
+	}
+	if len(defaultNamespace.Service.ListAllTasks()) != 0 {
+		t.Errorf("tasks of mission-a listed in the default namespace")
+	}
+
+	var names []string
+	for _, info := range registry.List() {
+		names = append(names, info.Name)
+	}
+	if len(names) != 2 || names[0] != Default || names[1] != "mission-a" {
+		t.Errorf("List() = %v, want [default mission-a]", names)
+	}
+
+	subscription, _ := missionA.Service.SubscribeEvents(missionA.Service.LastEventID())
+	defer subscription.Close()
+	if err := registry.Delete("mission-a"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-subscription.Events():
+		if ok {
+			t.Errorf("event streamed after Delete(), want the stream ended")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("event stream of the deleted namespace not ended")
+	}
+	if _, err := registry.Get("mission-a"); !errors.Is(err, ErrNamespaceNotFound) {
+		t.Errorf("Get() after Delete() error = %v, want %v", err, ErrNamespaceNotFound)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("task service of the deleted namespace not stopped")
+	}
+}
+
+func TestRegistry_Wait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	released := make(chan struct{})
+	registry := NewRegistry(ctx, service.DefaultConfig(), taskServiceMetrics, func(ctx context.Context, taskService *service.TaskService) {
+		<-ctx.Done()
+		<-released
+	})
+	if _, err := registry.Create("mission-a"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelWait()
+	if err := registry.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() with runners in flight error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(released)
+	if err := registry.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() error = %v", err)
+	}
+}
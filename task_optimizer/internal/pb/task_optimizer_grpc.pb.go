@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: task_optimizer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TaskOptimizer_AddTasks_FullMethodName     = "/taskoptimizer.v1.TaskOptimizer/AddTasks"
+	TaskOptimizer_ListTasks_FullMethodName    = "/taskoptimizer.v1.TaskOptimizer/ListTasks"
+	TaskOptimizer_ExecuteTasks_FullMethodName = "/taskoptimizer.v1.TaskOptimizer/ExecuteTasks"
+	TaskOptimizer_WatchEvents_FullMethodName  = "/taskoptimizer.v1.TaskOptimizer/WatchEvents"
+)
+
+// TaskOptimizerClient is the client API for TaskOptimizer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TaskOptimizer mirrors the task endpoints of the HTTP API.
+type TaskOptimizerClient interface {
+	// AddTasks adds the tasks to the queue, or none of them if any is invalid.
+	AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error)
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+	// ExecuteTasks removes from the queue the subset of compatible tasks with
+	// the highest profit under the constraints of the request, and returns it.
+	ExecuteTasks(ctx context.Context, in *ExecuteTasksRequest, opts ...grpc.CallOption) (*Execution, error)
+	// WatchEvents streams the queue and execution events, starting after
+	// last_event_id when set.
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type taskOptimizerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTaskOptimizerClient(cc grpc.ClientConnInterface) TaskOptimizerClient {
+	return &taskOptimizerClient{cc}
+}
+
+func (c *taskOptimizerClient) AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddTasksResponse)
+	err := c.cc.Invoke(ctx, TaskOptimizer_AddTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskOptimizerClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTasksResponse)
+	err := c.cc.Invoke(ctx, TaskOptimizer_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskOptimizerClient) ExecuteTasks(ctx context.Context, in *ExecuteTasksRequest, opts ...grpc.CallOption) (*Execution, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Execution)
+	err := c.cc.Invoke(ctx, TaskOptimizer_ExecuteTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *taskOptimizerClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &TaskOptimizer_ServiceDesc.Streams[0], TaskOptimizer_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TaskOptimizer_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
+// TaskOptimizerServer is the server API for TaskOptimizer service.
+// All implementations must embed UnimplementedTaskOptimizerServer
+// for forward compatibility.
+//
+// TaskOptimizer mirrors the task endpoints of the HTTP API.
+type TaskOptimizerServer interface {
+	// AddTasks adds the tasks to the queue, or none of them if any is invalid.
+	AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error)
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	// ExecuteTasks removes from the queue the subset of compatible tasks with
+	// the highest profit under the constraints of the request, and returns it.
+	ExecuteTasks(context.Context, *ExecuteTasksRequest) (*Execution, error)
+	// WatchEvents streams the queue and execution events, starting after
+	// last_event_id when set.
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedTaskOptimizerServer()
+}
+
+// UnimplementedTaskOptimizerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTaskOptimizerServer struct{}
+
+func (UnimplementedTaskOptimizerServer) AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTasks not implemented")
+}
+func (UnimplementedTaskOptimizerServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedTaskOptimizerServer) ExecuteTasks(context.Context, *ExecuteTasksRequest) (*Execution, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExecuteTasks not implemented")
+}
+func (UnimplementedTaskOptimizerServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedTaskOptimizerServer) mustEmbedUnimplementedTaskOptimizerServer() {}
+func (UnimplementedTaskOptimizerServer) testEmbeddedByValue()                       {}
+
+// UnsafeTaskOptimizerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TaskOptimizerServer will
+// result in compilation errors.
+type UnsafeTaskOptimizerServer interface {
+	mustEmbedUnimplementedTaskOptimizerServer()
+}
+
+func RegisterTaskOptimizerServer(s grpc.ServiceRegistrar, srv TaskOptimizerServer) {
+	// If the following call pancis, it indicates UnimplementedTaskOptimizerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TaskOptimizer_ServiceDesc, srv)
+}
+
+func _TaskOptimizer_AddTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskOptimizerServer).AddTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskOptimizer_AddTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskOptimizerServer).AddTasks(ctx, req.(*AddTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskOptimizer_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskOptimizerServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskOptimizer_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskOptimizerServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskOptimizer_ExecuteTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TaskOptimizerServer).ExecuteTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TaskOptimizer_ExecuteTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TaskOptimizerServer).ExecuteTasks(ctx, req.(*ExecuteTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TaskOptimizer_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TaskOptimizerServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type TaskOptimizer_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
+// TaskOptimizer_ServiceDesc is the grpc.ServiceDesc for TaskOptimizer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TaskOptimizer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "taskoptimizer.v1.TaskOptimizer",
+	HandlerType: (*TaskOptimizerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddTasks",
+			Handler:    _TaskOptimizer_AddTasks_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _TaskOptimizer_ListTasks_Handler,
+		},
+		{
+			MethodName: "ExecuteTasks",
+			Handler:    _TaskOptimizer_ExecuteTasks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _TaskOptimizer_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "task_optimizer.proto",
+}
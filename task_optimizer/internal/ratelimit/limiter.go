@@ -0,0 +1,82 @@
+// Package ratelimit limits the rate of requests of each caller with token
+// buckets.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is the sustained Rate of requests per second of a caller and the
+// Burst of requests it can make at once. A zero Rate means no limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// sweepSize is the number of buckets above which full buckets are dropped,
+// since they are the same as new ones.
+const sweepSize = 1024
+
+type bucket struct {
+	tokens float64
+	// updatedAt is when tokens was last refilled.
+	updatedAt time.Time
+}
+
+// Limiter holds a token bucket for each caller.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket of key, refilled at the rate of limit,
+// and reports whether there was one. If there wasn't, it returns how long
+// until there is.
+func (l *Limiter) Allow(key string, limit Limit) (bool, time.Duration) {
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+	burst := float64(max(limit.Burst, 1))
+	now := l.now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= sweepSize {
+			l.sweep(now, limit)
+		}
+		b = &bucket{tokens: burst, updatedAt: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate)
+	b.updatedAt = now
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / limit.Rate
+		return false, time.Duration(math.Ceil(wait * float64(time.Second)))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops the buckets that would be full at now. Buckets of callers with
+// a different limit are refilled at limit too, which at worst drops a
+// bucket a bit early.
+func (l *Limiter) sweep(now time.Time, limit Limit) {
+	burst := float64(max(limit.Burst, 1))
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*limit.Rate >= burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 3}
+
+	steps := []struct {
+		name          string
+		advance       time.Duration
+		key           string
+		want          bool
+		wantRetryWait time.Duration
+	}{
+		{"burst 1", 0, "a", true, 0},
+		{"burst 2", 0, "a", true, 0},
+		{"burst 3", 0, "a", true, 0},
+		{"bucket empty", 0, "a", false, 500 * time.Millisecond},
+		{"other caller", 0, "b", true, 0},
+		{"partially refilled", 250 * time.Millisecond, "a", false, 250 * time.Millisecond},
+		{"refilled", 250 * time.Millisecond, "a", true, 0},
+		{"refill capped at burst", time.Hour, "a", true, 0},
+		{"burst after refill 2", 0, "a", true, 0},
+		{"burst after refill 3", 0, "a", true, 0},
+		{"empty after refill", 0, "a", false, 500 * time.Millisecond},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		got, retryAfter := limiter.Allow(step.key, limit)
+		if got != step.want || retryAfter != step.wantRetryWait {
+			t.Errorf("%s: Allow() = %v, %v, want %v, %v", step.name, got, retryAfter, step.want, step.wantRetryWait)
+		}
+	}
+}
+
+func TestLimiter_Unlimited(t *testing.T) {
+	limiter := NewLimiter()
+	for i := 0; i < 100; i++ {
+		if ok, _ := limiter.Allow("a", Limit{}); !ok {
+			t.Fatal("Allow() without rate = false")
+		}
+	}
+	if len(limiter.buckets) != 0 {
+		t.Errorf("unlimited callers have %d buckets, want 0", len(limiter.buckets))
+	}
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 1}
+	for i := 0; i < sweepSize; i++ {
+		limiter.Allow(string(rune('a'+i%26))+time.Duration(i).String(), limit)
+	}
+	now = now.Add(time.Second)
+	limiter.Allow("new", limit)
+	if len(limiter.buckets) != 1 {
+		t.Errorf("buckets after sweep = %d, want 1", len(limiter.buckets))
+	}
+}
@@ -0,0 +1,87 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/pb"
+)
+
+// methodRoles is the role required by each method, the same as the role of
+// its HTTP route. Methods not listed require the admin role.
+var methodRoles = map[string]auth.Role{
+	pb.TaskOptimizer_AddTasks_FullMethodName:     auth.RoleSubmitter,
+	pb.TaskOptimizer_ListTasks_FullMethodName:    auth.RoleSubmitter,
+	pb.TaskOptimizer_ExecuteTasks_FullMethodName: auth.RoleOperator,
+	pb.TaskOptimizer_WatchEvents_FullMethodName:  auth.RoleSubmitter,
+}
+
+// UnaryAuthInterceptor authorizes unary calls by the API key of their
+// x-api-key or authorization metadata, like handler.Authorized does for HTTP
+// requests, and adds the identity of the caller to their context.
+func UnaryAuthInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, authenticator, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, request)
+	}
+}
+
+// StreamAuthInterceptor authorizes streaming calls like UnaryAuthInterceptor.
+func StreamAuthInterceptor(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(stream.Context(), authenticator, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(server, &contextStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+func authorize(ctx context.Context, authenticator *auth.Authenticator, method string) (context.Context, error) {
+	role, ok := methodRoles[method]
+	if !ok {
+		role = auth.RoleAdmin
+	}
+	identity, err := authenticator.Authorize(metadataKey(ctx), role)
+	if err != nil {
+		log.Warn().
+			Str("method", method).
+			Str("caller", identity.Name).
+			Err(err).Msg("request unauthorized")
+		if errors.Is(err, auth.ErrForbidden) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return auth.NewContext(ctx, identity), nil
+}
+
+func metadataKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get("x-api-key"); len(keys) > 0 && keys[0] != "" {
+		return keys[0]
+	}
+	if authorizations := md.Get("authorization"); len(authorizations) > 0 {
+		return auth.KeyFromAuthorization(authorizations[0])
+	}
+	return ""
+}
+
+// contextStream is a stream with its context replaced, such as by one holding
+// the identity of its caller or its logger.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
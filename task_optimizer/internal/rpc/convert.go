@@ -0,0 +1,184 @@
+package rpc
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/pb"
+	"time"
+)
+
+// The messages are converted from and to the DTOs, so requests are validated
+// and responses are built the same way as in the HTTP API.
+
+func taskFromPb(task *pb.Task) dto.Task {
+	taskDto := dto.Task{
+		Name:       task.GetName(),
+		Client:     task.GetClient(),
+		Resources:  task.GetResources(),
+		Profit:     task.GetProfit(),
+		Satellites: task.GetSatellites(),
+		Energy:     task.GetEnergy(),
+		Priority:   task.GetPriority(),
+		Pinned:     task.GetPinned(),
+		Held:       task.GetHeld(),
+	}
+	if task.GetExpiresAt() != nil {
+		expiresAt := task.GetExpiresAt().AsTime()
+		taskDto.ExpiresAt = &expiresAt
+	}
+	if decay := task.GetDecay(); decay != nil {
+		taskDto.Decay = &dto.Decay{
+			Type:        decay.GetType(),
+			RatePerHour: decay.GetRatePerHour(),
+			HalfLife:    decay.GetHalfLife(),
+			After:       decay.GetAfter(),
+			Factor:      decay.GetFactor(),
+		}
+	}
+	return taskDto
+}
+
+func taskToPb(task dto.Task) *pb.Task {
+	taskPb := &pb.Task{
+		Id:              task.ID,
+		Name:            task.Name,
+		Client:          task.Client,
+		Resources:       task.Resources,
+		Profit:          task.Profit,
+		EffectiveProfit: task.EffectiveProfit,
+		Satellites:      task.Satellites,
+		Energy:          task.Energy,
+		Priority:        task.Priority,
+		SubmittedAt:     timestamppb.New(task.SubmittedAt),
+		Pinned:          task.Pinned,
+		Held:            task.Held,
+		TemplateId:      task.TemplateID,
+		Status:          task.Status,
+		SubmittedBy:     task.SubmittedBy,
+	}
+	if task.ExpiresAt != nil {
+		taskPb.ExpiresAt = timestamppb.New(*task.ExpiresAt)
+	}
+	if task.Decay != nil {
+		taskPb.Decay = &pb.Decay{
+			Type:        task.Decay.Type,
+			RatePerHour: task.Decay.RatePerHour,
+			HalfLife:    task.Decay.HalfLife,
+			After:       task.Decay.After,
+			Factor:      task.Decay.Factor,
+		}
+	}
+	return taskPb
+}
+
+func taskQueryFromPb(request *pb.ListTasksRequest) dto.TaskQuery {
+	return dto.TaskQuery{
+		Resources: request.GetResources(),
+		Name:      request.GetName(),
+		MinProfit: request.MinProfit,
+		MaxProfit: request.MaxProfit,
+		Clients:   request.GetClients(),
+		Statuses:  request.GetStatuses(),
+		Sort:      request.GetSort(),
+		Cursor:    request.GetCursor(),
+		Limit:     int(request.GetLimit()),
+	}
+}
+
+func executionRequestFromPb(request *pb.ExecuteTasksRequest) dto.ExecutionRequest {
+	var requestDto dto.ExecutionRequest
+	if request.EnergyBudget != nil {
+		energyBudget := request.GetEnergyBudget()
+		requestDto.EnergyBudget = &energyBudget
+	}
+	if fairness := request.GetFairness(); fairness != nil {
+		requestDto.Fairness = &dto.Fairness{
+			Policy:          fairness.GetPolicy(),
+			MinClientShare:  fairness.GetMinClientShare(),
+			MaxClientProfit: fairness.GetMaxClientProfit(),
+			ClientWeights:   fairness.GetClientWeights(),
+		}
+	}
+	return requestDto
+}
+
+func executionRequestToPb(request dto.ExecutionRequest) *pb.ExecuteTasksRequest {
+	requestPb := &pb.ExecuteTasksRequest{
+		EnergyBudget: request.EnergyBudget,
+	}
+	if request.Fairness != nil {
+		requestPb.Fairness = &pb.Fairness{
+			Policy:          request.Fairness.Policy,
+			MinClientShare:  request.Fairness.MinClientShare,
+			MaxClientProfit: request.Fairness.MaxClientProfit,
+			ClientWeights:   request.Fairness.ClientWeights,
+		}
+	}
+	return requestPb
+}
+
+func executionToPb(execution dto.Execution) *pb.Execution {
+	executionPb := &pb.Execution{
+		PenaltyTotal: execution.PenaltyTotal,
+		EnergyUsed:   execution.EnergyUsed,
+		EnergyBudget: execution.EnergyBudget,
+		EnergyMargin: execution.EnergyMargin,
+		FairnessMet:  execution.FairnessMet,
+	}
+	for _, assignment := range execution.Tasks {
+		executionPb.Tasks = append(executionPb.Tasks, &pb.Assignment{
+			Task:      taskToPb(assignment.Task),
+			Satellite: assignment.Satellite,
+		})
+	}
+	for _, share := range execution.Clients {
+		executionPb.Clients = append(executionPb.Clients, &pb.ClientShare{
+			Client: share.Client,
+			Profit: share.Profit,
+			Tasks:  int64(share.Tasks),
+		})
+	}
+	for _, penalty := range execution.Penalties {
+		executionPb.Penalties = append(executionPb.Penalties, &pb.Penalty{
+			Tasks:     penalty.Tasks[:],
+			Resources: penalty.Resources,
+			Penalty:   penalty.Penalty,
+		})
+	}
+	for _, deferral := range execution.Deferred {
+		executionPb.Deferred = append(executionPb.Deferred, &pb.Deferral{
+			Task:     taskToPb(deferral.Task),
+			OutageId: deferral.OutageID,
+			Resource: deferral.Resource,
+			Until:    timestamppb.New(deferral.Until),
+		})
+	}
+	return executionPb
+}
+
+func eventToPb(id uint64, eventType string, eventTime time.Time, data any) *pb.Event {
+	eventPb := &pb.Event{
+		Id:   id,
+		Type: eventType,
+		Time: timestamppb.New(eventTime),
+	}
+	switch data := data.(type) {
+	case dto.Task:
+		eventPb.Payload = &pb.Event_TaskAdded{TaskAdded: taskToPb(data)}
+	case dto.TaskRemoval:
+		eventPb.Payload = &pb.Event_TaskRemoved{TaskRemoved: &pb.TaskRemoval{
+			Task:   taskToPb(data.Task),
+			Reason: data.Reason,
+		}}
+	case dto.ExecutionRequest:
+		eventPb.Payload = &pb.Event_ExecutionStarted{ExecutionStarted: executionRequestToPb(data)}
+	case dto.Execution:
+		eventPb.Payload = &pb.Event_ExecutionCompleted{ExecutionCompleted: executionToPb(data)}
+	case dto.ExecutionFailure:
+		eventPb.Payload = &pb.Event_ExecutionFailed{ExecutionFailed: &pb.ExecutionFailure{
+			Request: executionRequestToPb(data.Request),
+			Error:   data.Error,
+		}}
+	}
+	return eventPb
+}
@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/logging"
+	"time"
+)
+
+// RequestIDMetadataKey is the metadata key of the ID of a call, given by the
+// client or else generated, and sent back in the response header.
+const RequestIDMetadataKey = "x-request-id"
+
+// UnaryLoggingInterceptor logs the start and completion of unary calls with
+// their latency, like handler.Logged does for HTTP requests, and adds the
+// logger of the call to its context.
+func UnaryLoggingInterceptor(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	startTime := time.Now()
+	ctx, logger := callLogger(ctx, info.FullMethod)
+	logger.Info().Msg("request started")
+
+	response, err := handler(ctx, request)
+
+	logger.Info().
+		Str("code", status.Code(err).String()).
+		Dur("duration", time.Since(startTime)).
+		Msg("request completed")
+	return response, err
+}
+
+// StreamLoggingInterceptor logs the start and completion of streaming calls.
+func StreamLoggingInterceptor(server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	startTime := time.Now()
+	ctx, logger := callLogger(stream.Context(), info.FullMethod)
+	logger.Info().Msg("stream started")
+
+	err := handler(server, &contextStream{ServerStream: stream, ctx: ctx})
+
+	logger.Info().
+		Str("code", status.Code(err).String()).
+		Dur("duration", time.Since(startTime)).
+		Msg("stream completed")
+	return err
+}
+
+// callLogger returns the logger of the call, tagged with its request ID,
+// method and caller, and ctx holding it. The request ID is sent back in the
+// response header.
+func callLogger(ctx context.Context, method string) (context.Context, *zerolog.Logger) {
+	var requestID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDMetadataKey); len(values) > 0 {
+			requestID = values[0]
+		}
+	}
+	requestID = logging.RequestID(requestID)
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+	logger := logging.FromContext(ctx).With().
+		Str("request_id", requestID).
+		Str("method", method).
+		Str("caller", auth.FromContext(ctx).Name).Logger()
+	return logging.WithLogger(ctx, &logger), &logger
+}
@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"task_optimizer/internal/apierror"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/logging"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/pb"
+	"task_optimizer/internal/service"
+	"time"
+)
+
+// NamespaceMetadataKey is the metadata key of the namespace of a call, the
+// default namespace if it's missing.
+const NamespaceMetadataKey = "x-namespace"
+
+// TaskServer is the gRPC counterpart of controller.TaskController.
+type TaskServer struct {
+	pb.UnimplementedTaskOptimizerServer
+	namespaces *namespace.Registry
+}
+
+func NewTaskServer(namespaces *namespace.Registry) *TaskServer {
+	return &TaskServer{
+		namespaces: namespaces,
+	}
+}
+
+// taskService returns the task service of the namespace of the call.
+func (server *TaskServer) taskService(ctx context.Context) (*service.TaskService, error) {
+	name := namespace.Default
+	md, _ := metadata.FromIncomingContext(ctx)
+	if names := md.Get(NamespaceMetadataKey); len(names) > 0 && names[0] != "" {
+		name = names[0]
+	}
+	requested, err := server.namespaces.Get(name)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return requested.Service, nil
+}
+
+func (server *TaskServer) AddTasks(ctx context.Context, request *pb.AddTasksRequest) (*pb.AddTasksResponse, error) {
+	taskService, err := server.taskService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caller := auth.FromContext(ctx)
+	tasks := make([]model.Task, 0, len(request.GetTasks()))
+	for _, taskPb := range request.GetTasks() {
+		task, err := taskFromPb(taskPb).ToModel()
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if task.Pinned || task.Held {
+			// Pinning and holding tasks needs the operator role, like
+			// overriding them over HTTP.
+			if err := auth.Require(ctx, auth.RoleOperator); err != nil {
+				return nil, statusError(ctx, err)
+			}
+		}
+		task.SubmittedBy = caller.Name
+		tasks = append(tasks, task)
+	}
+	version, err := taskService.AddTasks(tasks, request.GetIfVersion())
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	return &pb.AddTasksResponse{Version: version}, nil
+}
+
+func (server *TaskServer) ListTasks(ctx context.Context, request *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	taskService, err := server.taskService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, err := taskQueryFromPb(request).ToModel()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	page, err := taskService.QueryTasks(query)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	pageDto := dto.TaskPageFromModel(page, time.Now())
+	response := &pb.ListTasksResponse{
+		Tasks:      make([]*pb.Task, 0, len(pageDto.Tasks)),
+		Total:      int64(pageDto.Total),
+		NextCursor: pageDto.NextCursor,
+		Version:    page.Version,
+	}
+	for _, task := range pageDto.Tasks {
+		response.Tasks = append(response.Tasks, taskToPb(task))
+	}
+	return response, nil
+}
+
+func (server *TaskServer) ExecuteTasks(ctx context.Context, request *pb.ExecuteTasksRequest) (*pb.Execution, error) {
+	taskService, err := server.taskService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	planRequest, err := executionRequestFromPb(request).ToModel()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	planRequest.IfVersion = request.GetIfVersion()
+	plan, err := taskService.GetHigherProfitSubset(ctx, planRequest)
+	if err != nil {
+		return nil, statusError(ctx, err)
+	}
+	execution := executionToPb(dto.ExecutionFromModel(plan))
+	execution.Version = plan.Version
+	return execution, nil
+}
+
+// WatchEvents streams the events of the service until the client cancels the
+// call. Streams lagging behind fail with ResourceExhausted, and clients have
+// to resume them from the last event received. Streams of a namespace being
+// deleted fail with NotFound.
+func (server *TaskServer) WatchEvents(request *pb.WatchEventsRequest, stream grpc.ServerStreamingServer[pb.Event]) error {
+	taskService, err := server.taskService(stream.Context())
+	if err != nil {
+		return err
+	}
+	subscription, missed := taskService.SubscribeEvents(request.GetLastEventId())
+	defer subscription.Close()
+	for _, event := range missed {
+		if err := sendEvent(stream, event); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscription.Events():
+			if !ok && subscription.BusClosed() {
+				return status.Error(codes.NotFound, "event stream closed, the namespace was deleted")
+			}
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "event stream lagging behind, resume from the last event received")
+			}
+			if err := sendEvent(stream, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendEvent(stream grpc.ServerStreamingServer[pb.Event], event events.Event) error {
+	return stream.Send(eventToPb(event.ID, string(event.Type), event.Time, dto.EventDataFromModel(event)))
+}
+
+// statusError returns the status of an error returned by the service, with
+// the code mapped like in every other transport, and logs it with the logger
+// of the call of ctx.
+func statusError(ctx context.Context, err error) error {
+	logging.FromContext(ctx).Err(err).Send()
+	return status.Error(apierror.CodeOf(err).GRPCCode(), err.Error())
+}
@@ -0,0 +1,278 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"strings"
+	"task_optimizer/internal/auth"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/namespace"
+	"task_optimizer/internal/pb"
+	"task_optimizer/internal/service"
+	"testing"
+)
+
+var taskServiceMetrics = metrics.NewTaskServiceMetricsVec()
+
+func newTestRegistry() *namespace.Registry {
+	return namespace.NewRegistry(context.Background(), service.DefaultConfig(), taskServiceMetrics, nil)
+}
+
+func newTestClient(t *testing.T, namespaces *namespace.Registry, options ...grpc.ServerOption) pb.TaskOptimizerClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(options...)
+	pb.RegisterTaskOptimizerServer(server, NewTaskServer(namespaces))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewTaskOptimizerClient(conn)
+}
+
+func TestTaskServer(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, newTestRegistry())
+
+	watch, err := client.WatchEvents(ctx, &pb.WatchEventsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.AddTasks(ctx, &pb.AddTasksRequest{Tasks: []*pb.Task{
+		{Name: "optical", Resources: []string{"camera"}, Profit: 2},
+		{Name: "sar", Resources: []string{"camera"}, Profit: 1},
+		{Name: "downlink", Resources: []string{"antenna"}, Profit: 1, Priority: "critical"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks.GetTasks()) != 3 {
+		t.Errorf("ListTasks() = %d tasks, want 3", len(tasks.GetTasks()))
+	}
+
+	execution, err := client.ExecuteTasks(ctx, &pb.ExecuteTasksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected := make(map[string]bool)
+	for _, assignment := range execution.GetTasks() {
+		selected[assignment.GetTask().GetName()] = true
+	}
+	if len(selected) != 2 || !selected["optical"] || !selected["downlink"] {
+		t.Errorf("ExecuteTasks() selected %v, want optical and downlink", selected)
+	}
+
+	wantEvents := []string{"TaskAdded", "TaskAdded", "TaskAdded", "ExecutionStarted", "TaskRemoved", "TaskRemoved", "ExecutionCompleted"}
+	for i, want := range wantEvents {
+		event, err := watch.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if event.GetType() != want || event.GetId() != uint64(i+1) {
+			t.Errorf("WatchEvents() event %d = %s, want %s", event.GetId(), event.GetType(), want)
+		}
+	}
+}
+
+func TestTaskServer_Errors(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, newTestRegistry(), grpc.UnaryInterceptor(UnaryAuthInterceptor(nil)))
+	_, err := client.AddTasks(ctx, &pb.AddTasksRequest{Tasks: []*pb.Task{
+		{Name: "a", Resources: []string{"camera"}, Pinned: true},
+		{Name: "b", Resources: []string{"camera"}, Pinned: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	negativeBudget := -1.0
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"invalid priority", func() error {
+			_, err := client.AddTasks(ctx, &pb.AddTasksRequest{Tasks: []*pb.Task{{Name: "a", Priority: "urgent"}}})
+			return err
+		}, codes.InvalidArgument},
+		{"negative energy budget", func() error {
+			_, err := client.ExecuteTasks(ctx, &pb.ExecuteTasksRequest{EnergyBudget: &negativeBudget})
+			return err
+		}, codes.InvalidArgument},
+		{"stale version", func() error {
+			_, err := client.ExecuteTasks(ctx, &pb.ExecuteTasksRequest{IfVersion: 1})
+			return err
+		}, codes.Aborted},
+		{"pinned tasks conflict", func() error {
+			_, err := client.ExecuteTasks(ctx, &pb.ExecuteTasksRequest{})
+			return err
+		}, codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskServer_Namespaces(t *testing.T) {
+	namespaces := newTestRegistry()
+	if _, err := namespaces.Create("ops"); err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, namespaces)
+	ops := metadata.AppendToOutgoingContext(context.Background(), NamespaceMetadataKey, "ops")
+
+	_, err := client.AddTasks(ops, &pb.AddTasksRequest{Tasks: []*pb.Task{{Name: "a", Resources: []string{"camera"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ctx, want := range map[context.Context]int{ops: 1, context.Background(): 0} {
+		tasks, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks.GetTasks()) != want {
+			t.Errorf("ListTasks() = %d tasks, want %d", len(tasks.GetTasks()), want)
+		}
+	}
+
+	unknown := metadata.AppendToOutgoingContext(context.Background(), NamespaceMetadataKey, "unknown")
+	if _, err := client.ListTasks(unknown, &pb.ListTasksRequest{}); status.Code(err) != codes.NotFound {
+		t.Errorf("ListTasks() in an unknown namespace code = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
+func TestAuthInterceptors(t *testing.T) {
+	authenticator, err := auth.NewAuthenticator(auth.KeyFile{Keys: []auth.KeyEntry{
+		{Name: "planner", Role: "submitter", Key: "planner-key"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClient(t, newTestRegistry(),
+		grpc.UnaryInterceptor(UnaryAuthInterceptor(authenticator)),
+		grpc.StreamInterceptor(StreamAuthInterceptor(authenticator)))
+	planner := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer planner-key")
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"no key", func() error {
+			_, err := client.ListTasks(context.Background(), &pb.ListTasksRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"invalid key", func() error {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "wrong")
+			_, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"submitter adds", func() error {
+			_, err := client.AddTasks(planner, &pb.AddTasksRequest{Tasks: []*pb.Task{{Name: "a", Resources: []string{"camera"}}}})
+			return err
+		}, codes.OK},
+		{"submitter can't pin", func() error {
+			_, err := client.AddTasks(planner, &pb.AddTasksRequest{Tasks: []*pb.Task{{Name: "b", Resources: []string{"camera"}, Pinned: true}}})
+			return err
+		}, codes.PermissionDenied},
+		{"submitter can't execute", func() error {
+			_, err := client.ExecuteTasks(planner, &pb.ExecuteTasksRequest{})
+			return err
+		}, codes.PermissionDenied},
+		{"stream without key", func() error {
+			watch, err := client.WatchEvents(context.Background(), &pb.WatchEventsRequest{})
+			if err != nil {
+				return err
+			}
+			_, err = watch.Recv()
+			return err
+		}, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	tasks, err := client.ListTasks(planner, &pb.ListTasksRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks.GetTasks()) != 1 || tasks.GetTasks()[0].GetSubmittedBy() != "planner" {
+		t.Errorf("ListTasks() = %v, want a task submitted by planner", tasks.GetTasks())
+	}
+}
+
+func TestLoggingInterceptors_RequestID(t *testing.T) {
+	var logs bytes.Buffer
+	globalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = globalLogger }()
+	client := newTestClient(t, newTestRegistry(),
+		grpc.UnaryInterceptor(UnaryLoggingInterceptor),
+		grpc.StreamInterceptor(StreamLoggingInterceptor))
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadataKey, "7d2f4c1e")
+	if _, err := client.ListTasks(ctx, &pb.ListTasksRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(RequestIDMetadataKey); len(got) != 1 || got[0] != "7d2f4c1e" {
+		t.Errorf("request ID = %v, want the given 7d2f4c1e", got)
+	}
+	if _, err := client.ListTasks(context.Background(), &pb.ListTasksRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatal(err)
+	}
+	if got := header.Get(RequestIDMetadataKey); len(got) != 1 || got[0] == "" {
+		t.Errorf("request ID = %v, want a generated one", got)
+	}
+
+	logs.Reset()
+	ctx = metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadataKey, "9a1b", NamespaceMetadataKey, "unknown")
+	if _, err := client.ListTasks(ctx, &pb.ListTasksRequest{}); status.Code(err) != codes.NotFound {
+		t.Fatalf("ListTasks() in an unknown namespace error = %v, want %v", err, codes.NotFound)
+	}
+	var errorLogged bool
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		if entry["level"] == "error" {
+			errorLogged = true
+			if entry["request_id"] != "9a1b" {
+				t.Errorf("error logged with request ID %v, want 9a1b", entry["request_id"])
+			}
+		}
+	}
+	if !errorLogged {
+		t.Errorf("error not logged, logs: %s", logs.String())
+	}
+}
@@ -0,0 +1,160 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"task_optimizer/internal/ds/set"
+	"time"
+)
+
+// Schedule returns the next activation time strictly after a given time.
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+// Interval activates at a fixed interval.
+type Interval time.Duration
+
+func (i Interval) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(i))
+}
+
+// Cron activates on the minutes matched by a standard five field cron
+// expression: minute, hour, day of month, month and day of week.
+type Cron struct {
+	minutes     set.Set[int]
+	hours       set.Set[int]
+	daysOfMonth set.Set[int]
+	months      set.Set[int]
+	daysOfWeek  set.Set[int]
+
+	// anyDayOfMonth and anyDayOfWeek record whether the day fields were "*".
+	// As in cron, when both are restricted a day matches if either does.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// ParseCron parses a cron expression. Each field accepts "*", values, ranges
+// ("1-5"), steps ("*/15", "0-30/10") and comma separated lists of them. Day
+// of week 7 is Sunday, as 0.
+func ParseCron(expression string) (Cron, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return Cron{}, fmt.Errorf("cron expression %q must have %d fields", expression, len(cronFields))
+	}
+	values := make([]set.Set[int], len(fields))
+	for i, field := range fields {
+		var err error
+		if values[i], err = parseCronField(field, cronFields[i]); err != nil {
+			return Cron{}, err
+		}
+	}
+	if values[4].Contains(7) {
+		values[4].Remove(7).Add(0)
+	}
+
+	return Cron{
+		minutes:       values[0],
+		hours:         values[1],
+		daysOfMonth:   values[2],
+		months:        values[3],
+		daysOfWeek:    values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, spec cronField) (set.Set[int], error) {
+	values := set.Empty[int]()
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q in %s field", stepPart, spec.name)
+			}
+		}
+
+		first, last := spec.min, spec.max
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if first, err = strconv.Atoi(startPart); err != nil {
+				return nil, fmt.Errorf("invalid value %q in %s field", startPart, spec.name)
+			}
+			last = first
+			if isRange {
+				if last, err = strconv.Atoi(endPart); err != nil {
+					return nil, fmt.Errorf("invalid value %q in %s field", endPart, spec.name)
+				}
+			} else if hasStep {
+				last = spec.max
+			}
+		}
+		if first < spec.min || last > spec.max || first > last {
+			return nil, fmt.Errorf("%s field %q out of range %d-%d", spec.name, part, spec.min, spec.max)
+		}
+		for value := first; value <= last; value += step {
+			values.Add(value)
+		}
+	}
+
+	return values, nil
+}
+
+// maxCronSearch bounds the search of the next activation, so expressions that
+// never match (like February 30th) don't loop forever.
+const maxCronSearch = 5 * 366 * 24 * time.Hour
+
+// Next returns the first matching minute after the given time, or the zero
+// time if the expression doesn't match in the next five years.
+func (c Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronSearch)
+	for t.Before(limit) {
+		switch {
+		case !c.months.Contains(int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hours.Contains(t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minutes.Contains(t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth.Contains(t.Day())
+	dayOfWeek := c.daysOfWeek.Contains(int(t.Weekday()))
+	switch {
+	case c.anyDayOfMonth && c.anyDayOfWeek:
+		return true
+	case c.anyDayOfMonth:
+		return dayOfWeek
+	case c.anyDayOfWeek:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
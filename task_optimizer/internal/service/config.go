@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// Solver is the algorithm that searches the compatibility graph for the plan.
+type Solver string
+
+const (
+	// SolverAuto runs Bron-Kerbosch on requests without energy budget,
+	// fairness policy, penalties or pinned tasks, and branch and bound on the
+	// rest.
+	SolverAuto Solver = "auto"
+	// SolverBranchAndBound runs branch and bound on every request, so that
+	// every search is bound by the solver timeout.
+	SolverBranchAndBound Solver = "branch-and-bound"
+)
+
+func ParseSolver(name string) (Solver, error) {
+	switch solver := Solver(name); solver {
+	case SolverAuto, SolverBranchAndBound:
+		return solver, nil
+	default:
+		return "", fmt.Errorf("unknown solver %q", name)
+	}
+}
+
+type Config struct {
+	Priorities PriorityConfig
+	// Conflicts holds the resources that tasks can share paying a profit
+	// penalty, the rest of the resources can't be shared.
+	Conflicts model.ConflictModel
+	// ReaperInterval is how often expired tasks are evicted.
+	ReaperInterval time.Duration
+	// SchedulerInterval is how often task templates are checked for due
+	// activations.
+	SchedulerInterval time.Duration
+	// EventBufferSize is the number of events kept for subscribers resuming
+	// their stream.
+	EventBufferSize int
+	// MaxTasksPerRequest is the number of tasks that a request can add, 0
+	// for no limit.
+	MaxTasksPerRequest int
+	Solver             Solver
+	// SolverTimeout is how long a branch and bound search can take, 0 for no
+	// limit. When it runs out the best plan found so far is returned.
+	SolverTimeout time.Duration
+}
+
+type PriorityConfig struct {
+	// AgingBoost is the profit added to a task of each priority class for
+	// every hour it waits in the list, so low priority tasks don't starve.
+	AgingBoost map[model.Priority]float64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Priorities: PriorityConfig{
+			AgingBoost: map[model.Priority]float64{
+				model.PriorityBestEffort: 0.1,
+			},
+		},
+		ReaperInterval:     time.Minute,
+		SchedulerInterval:  10 * time.Second,
+		EventBufferSize:    1024,
+		MaxTasksPerRequest: 10000,
+		Solver:             SolverAuto,
+	}
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"sort"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/ds/taskgraph"
+	"task_optimizer/internal/model"
+)
+
+// planPenalties returns the penalties paid for the pairs of nodes of the
+// clique that share resources.
+func planPenalties(compatibilityGraph taskgraph.TaskCompatibilityGraph, clique set.Set[int]) []model.Penalty {
+	nodes := clique.Slice()
+	sort.Ints(nodes)
+	penalties := []model.Penalty{}
+	for i, node := range nodes {
+		for _, other := range nodes[i+1:] {
+			amount := compatibilityGraph.GetPenalty(node, other)
+			if amount <= 0 {
+				continue
+			}
+			task, otherTask := compatibilityGraph.GetTask(node), compatibilityGraph.GetTask(other)
+			resources := task.Resources.Intersect(otherTask.Resources).Slice()
+			sort.Strings(resources)
+			penalties = append(penalties, model.Penalty{
+				Tasks:     [2]model.Task{task, otherTask},
+				Resources: resources,
+				Amount:    amount,
+			})
+		}
+	}
+	return penalties
+}
@@ -0,0 +1,27 @@
+package service
+
+import "task_optimizer/internal/events"
+
+// SubscribeEvents subscribes to the events of the service, returning the
+// buffered events after lastEventID to resume a previous subscription.
+func (s *TaskService) SubscribeEvents(lastEventID uint64) (*events.Subscription, []events.Event) {
+	return s.events.Subscribe(lastEventID)
+}
+
+// LastEventID returns the ID of the last event published by the service.
+func (s *TaskService) LastEventID() uint64 {
+	return s.events.LastID()
+}
+
+// SubscribeCompletedExecutions subscribes to the ExecutionCompleted events of
+// the service like SubscribeEvents, but the subscription is never dropped for
+// lagging behind so that no execution is missed.
+func (s *TaskService) SubscribeCompletedExecutions(lastEventID uint64) (*events.Subscription, []events.Event) {
+	return s.events.SubscribeReliable(lastEventID, events.ExecutionCompleted)
+}
+
+// Close ends the event subscriptions of the service, such as the event
+// streams of its clients, once the service is no longer used.
+func (s *TaskService) Close() {
+	s.events.Close()
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// RunReaper evicts expired tasks and ended outages every ReaperInterval until
+// ctx is done.
+func (s *TaskService) RunReaper(ctx context.Context) {
+	ticker := time.NewTicker(s.config.ReaperInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := s.now()
+			s.tasksMu.Lock()
+			s.evictExpiredTasks(now)
+			s.tasksMu.Unlock()
+			s.evictEndedOutages(now)
+		}
+	}
+}
+
+// evictExpiredTasks removes the tasks expired at now from the list. The
+// caller must hold tasksMu.
+func (s *TaskService) evictExpiredTasks(now time.Time) {
+	remainingTasks := make([]model.Task, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		if !task.IsExpired(now) {
+			remainingTasks = append(remainingTasks, task)
+			continue
+		}
+		log.Info().
+			Uint64("task_id", task.ID).
+			Str("task_name", task.Name).
+			Time("expires_at", task.ExpiresAt).
+			Msg("expired task evicted")
+		s.metrics.EvictedTasks.Inc()
+		s.events.Publish(events.TaskRemoved, events.TaskRemoval{Task: task, Reason: events.RemovalExpired})
+	}
+	if len(remainingTasks) < len(s.tasks) {
+		s.version++
+	}
+	s.tasks = remainingTasks
+	s.metrics.TaskListSize.Set(float64(len(s.tasks)))
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/model"
+	"testing"
+	"time"
+)
+
+func TestTaskService_RunReaper(t *testing.T) {
+	config := DefaultConfig()
+	config.ReaperInterval = time.Millisecond
+	s := NewTaskService(config, taskServiceMetrics)
+	start := time.Now()
+	var clock atomic.Int64
+	clock.Store(start.UnixNano())
+	s.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	if _, err := s.AddTasks([]model.Task{
+		{Name: "capture", Resources: set.Of("camera"), Profit: 1, ExpiresAt: start.Add(time.Hour)},
+		{Name: "downlink", Resources: set.Of("antenna"), Profit: 1, ExpiresAt: start.Add(3 * time.Hour)},
+		{Name: "calibration", Resources: set.Of("camera"), Profit: 1},
+	}, AnyVersion); err != nil {
+		t.Fatal(err)
+	}
+	names := func() []string {
+		var names []string
+		for _, task := range s.ListAllTasks() {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+	// waitFor waits for the reaper to leave the tasks named want.
+	waitFor := func(want []string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !reflect.DeepEqual(names(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("tasks = %v, want %v", names(), want)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.RunReaper(ctx)
+		close(done)
+	}()
+
+	// Nothing has expired yet, so several ticks keep every task.
+	time.Sleep(20 * time.Millisecond)
+	waitFor([]string{"capture", "downlink", "calibration"})
+
+	clock.Store(start.Add(2 * time.Hour).UnixNano())
+	waitFor([]string{"downlink", "calibration"})
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunReaper() didn't return after ctx was canceled")
+	}
+	// Once stopped the reaper evicts nothing more.
+	clock.Store(start.Add(4 * time.Hour).UnixNano())
+	time.Sleep(20 * time.Millisecond)
+	if got, want := names(), []string{"downlink", "calibration"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks after the reaper stopped = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"math"
+	"task_optimizer/internal/ds/graph"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/ds/taskgraph"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// cancelableObjective stops the search of Objective when ctx is done, no
+// clique is extendable from then on.
+type cancelableObjective struct {
+	graph.Objective
+	ctx context.Context
+}
+
+func (o cancelableObjective) Extendable(clique set.Set[int]) bool {
+	return o.ctx.Err() == nil && o.Objective.Extendable(clique)
+}
+
+// planObjective scores the cliques of the compatibility graph for a plan
+// request: it keeps the energy of the plan within the budget and applies the
+// fairness policy. Without a proportional policy the score of a clique is its
+// weight in the graph minus the penalties of the resources its tasks share.
+type planObjective struct {
+	graph    taskgraph.TaskCompatibilityGraph
+	budget   float64
+	fairness model.Fairness
+
+	// taskIDs, clients, profits and agedProfits of each node, and all the
+	// clients with tasks in the graph.
+	taskIDs     []uint64
+	clients     []string
+	profits     []float64
+	agedProfits []float64
+	allClients  set.Set[string]
+	// criticalWeight is the score of a critical task under the proportional
+	// policy, bigger than the score of all the tasks together plus the
+	// penalties of every pair of them.
+	criticalWeight float64
+}
+
+func (s *TaskService) newPlanObjective(compatibilityGraph taskgraph.TaskCompatibilityGraph, request model.PlanRequest, now time.Time) *planObjective {
+	nodes := len(compatibilityGraph.GetNodes())
+	objective := &planObjective{
+		graph:       compatibilityGraph,
+		budget:      request.EnergyBudget,
+		fairness:    request.Fairness,
+		taskIDs:     make([]uint64, nodes),
+		clients:     make([]string, nodes),
+		profits:     make([]float64, nodes),
+		agedProfits: make([]float64, nodes),
+		allClients:  set.Empty[string](),
+	}
+	positiveAgedProfits := make(map[string]float64)
+	for node := 0; node < nodes; node++ {
+		task := compatibilityGraph.GetTask(node)
+		objective.taskIDs[node] = task.ID
+		objective.clients[node] = task.Client
+		objective.profits[node] = task.EffectiveProfit(now)
+		objective.agedProfits[node] = s.agedProfit(task, now)
+		objective.allClients.Add(task.Client)
+		positiveAgedProfits[task.Client] += math.Max(0, objective.agedProfits[node])
+	}
+	objective.criticalWeight = 1 + compatibilityGraph.PenaltyTotal()
+	for client, agedProfit := range positiveAgedProfits {
+		objective.criticalWeight += request.Fairness.ClientWeight(client) * math.Log1p(agedProfit)
+	}
+
+	return objective
+}
+
+func (o *planObjective) Score(clique set.Set[int]) (float64, bool) {
+	if o.fairness.Policy == model.FairnessMinShare && !o.meetsMinShare(clique) {
+		return 0, false
+	}
+	if o.fairness.Policy != model.FairnessProportional {
+		var weight float64
+		for node := range clique {
+			weight += o.graph.GetWeight(node)
+		}
+		return weight - graph.CliquePenalty(clique, o.graph), true
+	}
+
+	var score float64
+	agedProfits := o.penalizedAgedProfits(clique)
+	for node := range clique {
+		if o.graph.GetTask(node).Priority == model.PriorityCritical {
+			score += o.criticalWeight
+		}
+	}
+	for client, agedProfit := range agedProfits {
+		score += o.fairness.ClientWeight(client) * math.Log1p(math.Max(0, agedProfit))
+	}
+	return score, true
+}
+
+func (o *planObjective) Bound(clique, candidates set.Set[int]) float64 {
+	// A clique has at most one node of each task, the one of the satellite
+	// it's assigned to in a fleet, so only the best node of each candidate
+	// task adds to the bound.
+	bestCandidates := make(map[uint64]int)
+	for node := range candidates {
+		best, ok := bestCandidates[o.taskIDs[node]]
+		if !ok || o.graph.GetWeight(node) > o.graph.GetWeight(best) {
+			bestCandidates[o.taskIDs[node]] = node
+		}
+	}
+
+	// Adding nodes can only add penalties, so the penalties of the clique
+	// bound the penalties of any clique containing it.
+	if o.fairness.Policy != model.FairnessProportional {
+		bound := -graph.CliquePenalty(clique, o.graph)
+		for node := range clique {
+			bound += o.graph.GetWeight(node)
+		}
+		for _, node := range bestCandidates {
+			bound += math.Max(0, o.graph.GetWeight(node))
+		}
+		return bound
+	}
+
+	// The score grows with the profit of each client and with the number of
+	// critical tasks, so adding every candidate task bounds it.
+	all := clique.Clone()
+	for _, node := range bestCandidates {
+		all.Add(node)
+	}
+	var bound float64
+	agedProfits := o.penalizedAgedProfits(clique)
+	for node := range all {
+		if !clique.Contains(node) && o.agedProfits[node] > 0 {
+			agedProfits[o.clients[node]] += o.agedProfits[node]
+		}
+		if o.graph.GetTask(node).Priority == model.PriorityCritical {
+			bound += o.criticalWeight
+		}
+	}
+	for client, agedProfit := range agedProfits {
+		bound += o.fairness.ClientWeight(client) * math.Log1p(math.Max(0, agedProfit))
+	}
+	return bound
+}
+
+// penalizedAgedProfits returns the aged profit of each client in the clique,
+// where the penalty of each pair of tasks sharing resources is split between
+// the clients of both tasks.
+func (o *planObjective) penalizedAgedProfits(clique set.Set[int]) map[string]float64 {
+	agedProfits := make(map[string]float64)
+	nodes := clique.Slice()
+	for i, node := range nodes {
+		agedProfits[o.clients[node]] += o.agedProfits[node]
+		for _, other := range nodes[i+1:] {
+			if penalty := o.graph.GetPenalty(node, other); penalty > 0 {
+				agedProfits[o.clients[node]] -= penalty / 2
+				agedProfits[o.clients[other]] -= penalty / 2
+			}
+		}
+	}
+	return agedProfits
+}
+
+func (o *planObjective) Extendable(clique set.Set[int]) bool {
+	var energy float64
+	profits := make(map[string]float64)
+	for node := range clique {
+		energy += o.graph.GetCost(node)
+		profits[o.clients[node]] += o.profits[node]
+	}
+	if energy > o.budget {
+		return false
+	}
+	if o.fairness.Policy == model.FairnessCap {
+		for _, profit := range profits {
+			if profit > o.fairness.MaxClientProfit {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// meetsMinShare reports whether every client with tasks in the graph gets
+// at least the minimum share of the profit of the clique. The empty clique
+// only meets it when the graph is empty.
+func (o *planObjective) meetsMinShare(clique set.Set[int]) bool {
+	if len(clique) == 0 {
+		return len(o.profits) == 0
+	}
+	var total float64
+	profits := make(map[string]float64)
+	for node := range clique {
+		profits[o.clients[node]] += o.profits[node]
+		total += o.profits[node]
+	}
+	for client := range o.allClients {
+		if profits[client] < o.fairness.MinShare*total {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// AddOutage registers the outage assigning its ID, and returns it. The
+// resource of the outage must be in the catalog.
+func (s *TaskService) AddOutage(outage model.Outage) (model.Outage, error) {
+	if err := outage.Validate(); err != nil {
+		return model.Outage{}, err
+	}
+	s.resourcesMu.RLock()
+	err := s.resources.Validate(outage.Resource)
+	s.resourcesMu.RUnlock()
+	if err != nil {
+		return model.Outage{}, fmt.Errorf("%w: %s", ErrUnknownResource, outage.Resource)
+	}
+
+	s.outagesMu.Lock()
+	defer s.outagesMu.Unlock()
+	if s.outages == nil {
+		s.outages = make(map[uint64]model.Outage)
+	}
+	s.lastOutageID++
+	outage.ID = s.lastOutageID
+	s.outages[outage.ID] = outage
+	return outage, nil
+}
+
+// ListOutages returns the outages sorted by start time.
+func (s *TaskService) ListOutages() []model.Outage {
+	s.outagesMu.RLock()
+	outages := make([]model.Outage, 0, len(s.outages))
+	for _, outage := range s.outages {
+		outages = append(outages, outage)
+	}
+	s.outagesMu.RUnlock()
+
+	sort.Slice(outages, func(i, j int) bool {
+		if !outages[i].Start.Equal(outages[j].Start) {
+			return outages[i].Start.Before(outages[j].Start)
+		}
+		return outages[i].ID < outages[j].ID
+	})
+	return outages
+}
+
+func (s *TaskService) RemoveOutage(id uint64) bool {
+	s.outagesMu.Lock()
+	defer s.outagesMu.Unlock()
+	if _, ok := s.outages[id]; !ok {
+		return false
+	}
+	delete(s.outages, id)
+	return true
+}
+
+// activeOutages returns the outages active at the given time.
+func (s *TaskService) activeOutages(at time.Time) []model.Outage {
+	var active []model.Outage
+	for _, outage := range s.ListOutages() {
+		if outage.IsActive(at) {
+			active = append(active, outage)
+		}
+	}
+	return active
+}
+
+// evictEndedOutages removes the outages ended at now.
+func (s *TaskService) evictEndedOutages(now time.Time) {
+	s.outagesMu.Lock()
+	defer s.outagesMu.Unlock()
+	for id, outage := range s.outages {
+		if !outage.End.After(now) {
+			log.Info().
+				Uint64("outage_id", id).
+				Str("resource", outage.Resource).
+				Msg("ended outage evicted")
+			delete(s.outages, id)
+		}
+	}
+}
+
+// outageDeferral returns the deferral of the task if any of the outages
+// affects it.
+func outageDeferral(task model.Task, outages []model.Outage, catalog model.ResourceCatalog) (model.Deferral, bool) {
+	for _, outage := range outages {
+		if outage.Affects(task, catalog) {
+			return model.Deferral{Task: task, Outage: outage}, true
+		}
+	}
+	return model.Deferral{}, false
+}
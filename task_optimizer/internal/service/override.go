@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"task_optimizer/internal/ds/graph"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/ds/taskgraph"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+var (
+	ErrTaskNotFound    = errors.New("task not found")
+	ErrPinnedAndHeld   = errors.New("task can't be pinned and held at the same time")
+	ErrPinnedConflict  = errors.New("pinned tasks conflict")
+	ErrPinnedNotServed = errors.New("pinned task can't be served by any satellite")
+	// ErrPinnedOverBudget is returned when the pinned tasks alone exceed the
+	// energy budget or the profit cap of a client.
+	ErrPinnedOverBudget = errors.New("pinned tasks exceed the constraints of the execution")
+)
+
+// SetTaskOverrides sets the pinned and held flags of the task with the given
+// ID, leaving unchanged the flags that are nil, if the list is at ifVersion,
+// unless it's AnyVersion. It returns the task and the new version of the
+// list.
+func (s *TaskService) SetTaskOverrides(id uint64, pinned, held *bool, ifVersion uint64) (model.Task, uint64, error) {
+	s.tasksMu.Lock()
+	defer s.tasksMu.Unlock()
+	if err := s.checkVersion(ifVersion); err != nil {
+		return model.Task{}, 0, err
+	}
+	i := slices.IndexFunc(s.tasks, func(task model.Task) bool {
+		return task.ID == id
+	})
+	if i < 0 {
+		return model.Task{}, 0, ErrTaskNotFound
+	}
+
+	task := s.tasks[i]
+	if pinned != nil {
+		task.Pinned = *pinned
+	}
+	if held != nil {
+		task.Held = *held
+	}
+	if task.Pinned && task.Held {
+		return model.Task{}, 0, ErrPinnedAndHeld
+	}
+	// The list is shared with readers of ListAllTasks, so it's copied
+	// instead of updated in place.
+	s.tasks = slices.Clone(s.tasks)
+	s.tasks[i] = task
+	s.version++
+	return task, s.version, nil
+}
+
+// pinnedGroups returns the nodes of each pinned task in the graph, for the
+// search to pick one of each. Without a fleet a task has only one node; with
+// a fleet it has one for each satellite that can serve it.
+func pinnedGroups(compatibilityGraph taskgraph.TaskCompatibilityGraph, pinnedTasks []model.Task) ([]set.Set[int], error) {
+	nodesByTask := make(map[uint64]set.Set[int], len(pinnedTasks))
+	for node := range compatibilityGraph.GetNodes() {
+		if task := compatibilityGraph.GetTask(node); task.Pinned {
+			if nodesByTask[task.ID] == nil {
+				nodesByTask[task.ID] = set.Empty[int]()
+			}
+			nodesByTask[task.ID].Add(node)
+		}
+	}
+	groups := make([]set.Set[int], 0, len(pinnedTasks))
+	for _, task := range pinnedTasks {
+		if len(nodesByTask[task.ID]) == 0 {
+			return nil, fmt.Errorf("%w: task %d (%s)", ErrPinnedNotServed, task.ID, task.Name)
+		}
+		groups = append(groups, nodesByTask[task.ID])
+	}
+	return groups, nil
+}
+
+// pinnedError returns why no plan has every pinned task: either they can't
+// be executed together or they exceed the constraints of the execution.
+func (s *TaskService) pinnedError(compatibilityGraph taskgraph.TaskCompatibilityGraph, pinnedTasks []model.Task, groups []set.Set[int], now time.Time) error {
+	// Only the pinned nodes are candidates, so the search is small.
+	pinnedNodes := set.Empty[int]()
+	for _, group := range groups {
+		for node := range group {
+			pinnedNodes.Add(node)
+		}
+	}
+	objective := s.newPlanObjective(compatibilityGraph, model.UnconstrainedPlanRequest(), now)
+	if _, _, found := graph.MaxScoreCliqueCovering(set.Empty[int](), pinnedNodes, groups, compatibilityGraph, objective); found {
+		return ErrPinnedOverBudget
+	}
+	return pinnedConflict(compatibilityGraph, pinnedTasks, groups)
+}
+
+// pinnedConflict returns an error naming a pair of the pinned tasks that
+// can't be executed together, if there is one.
+func pinnedConflict(compatibilityGraph taskgraph.TaskCompatibilityGraph, pinnedTasks []model.Task, groups []set.Set[int]) error {
+	for i, task := range pinnedTasks {
+		for j := i + 1; j < len(pinnedTasks); j++ {
+			compatible := false
+			for node := range groups[i] {
+				compatible = compatible || len(groups[j].Intersect(compatibilityGraph.GetNeighbors(node))) > 0
+			}
+			if !compatible {
+				other := pinnedTasks[j]
+				return fmt.Errorf("%w: task %d (%s) and task %d (%s)", ErrPinnedConflict, task.ID, task.Name, other.ID, other.Name)
+			}
+		}
+	}
+	return fmt.Errorf("%w: no satellite assignment fits all the pinned tasks", ErrPinnedConflict)
+}
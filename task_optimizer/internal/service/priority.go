@@ -0,0 +1,34 @@
+package service
+
+import (
+	"math"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// agedProfit returns the effective profit of the task plus the aging boost of
+// its priority class for the time it has been waiting.
+func (s *TaskService) agedProfit(task model.Task, now time.Time) float64 {
+	waited := now.Sub(task.SubmittedAt).Hours()
+	return task.EffectiveProfit(now) + s.config.Priorities.AgingBoost[task.Priority]*waited
+}
+
+// taskWeight returns the weight of the tasks in the compatibility graph. The
+// weight of a critical task is bigger than the aged profit of all the tasks
+// together plus penaltyTotal, the penalties of every pair of tasks, so
+// maximizing the weight minus the penalties is the same as maximizing the
+// number of critical tasks first and then the aged profit.
+func (s *TaskService) taskWeight(tasks []model.Task, penaltyTotal float64, now time.Time) func(task model.Task) float64 {
+	criticalWeight := 1.0 + penaltyTotal
+	for _, task := range tasks {
+		criticalWeight += math.Abs(s.agedProfit(task, now))
+	}
+
+	return func(task model.Task) float64 {
+		weight := s.agedProfit(task, now)
+		if task.Priority == model.PriorityCritical {
+			weight += criticalWeight
+		}
+		return weight
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// taskCursor is the position of a page, the sort key and ID of the last task
+// of the previous page. Positions don't depend on the tasks still being in
+// the list, so pages are stable while tasks are added and removed.
+type taskCursor struct {
+	Sort        model.TaskSort `json:"s"`
+	Descending  bool           `json:"d"`
+	ID          uint64         `json:"i"`
+	Profit      float64        `json:"p,omitempty"`
+	SubmittedAt time.Time      `json:"t,omitempty"`
+}
+
+func encodeTaskCursor(query model.TaskQuery, task model.Task) string {
+	data, _ := json.Marshal(taskCursor{
+		Sort:        query.Sort,
+		Descending:  query.Descending,
+		ID:          task.ID,
+		Profit:      task.Profit,
+		SubmittedAt: task.SubmittedAt,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeTaskCursor returns a task at the position of the cursor.
+func decodeTaskCursor(query model.TaskQuery) (model.Task, error) {
+	data, err := base64.RawURLEncoding.DecodeString(query.Cursor)
+	if err != nil {
+		return model.Task{}, ErrInvalidCursor
+	}
+	var cursor taskCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return model.Task{}, ErrInvalidCursor
+	}
+	if cursor.Sort != query.Sort || cursor.Descending != query.Descending {
+		return model.Task{}, fmt.Errorf("%w: cursor of a different sort", ErrInvalidCursor)
+	}
+	return model.Task{
+		ID:          cursor.ID,
+		Profit:      cursor.Profit,
+		SubmittedAt: cursor.SubmittedAt,
+	}, nil
+}
+
+// QueryTasks returns the page of the tasks in the list matching the query,
+// in the order of the query.
+func (s *TaskService) QueryTasks(query model.TaskQuery) (model.TaskPage, error) {
+	var after model.Task
+	if query.Cursor != "" {
+		var err error
+		if after, err = decodeTaskCursor(query); err != nil {
+			return model.TaskPage{}, err
+		}
+	}
+
+	now := time.Now()
+	allTasks, version := s.Snapshot()
+	var tasks []model.Task
+	for _, task := range allTasks {
+		if query.Matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortFunc(tasks, query.Compare)
+	page := model.TaskPage{Total: len(tasks), Version: version}
+	if query.Cursor != "" {
+		start, _ := slices.BinarySearchFunc(tasks, after, query.Compare)
+		if start < len(tasks) && query.Compare(tasks[start], after) == 0 {
+			start++
+		}
+		tasks = tasks[start:]
+	}
+	if query.Limit > 0 && len(tasks) > query.Limit {
+		tasks = tasks[:query.Limit]
+		page.NextCursor = encodeTaskCursor(query, tasks[len(tasks)-1])
+	}
+	page.Tasks = tasks
+	return page, nil
+}
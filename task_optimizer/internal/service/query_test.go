@@ -0,0 +1,91 @@
+package service
+
+import (
+	"reflect"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/model"
+	"testing"
+)
+
+var taskServiceMetrics = metrics.NewTaskServiceMetricsVec().ForNamespace("default")
+
+func TestTaskService_QueryTasks(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	s.AddTasks([]model.Task{
+		{Name: "Optical capture", Client: "acme", Resources: set.Of("camera"), Profit: 3},
+		{Name: "SAR capture", Client: "globex", Resources: set.Of("camera", "disk"), Profit: 1},
+		{Name: "Clean disk", Resources: set.Of("disk"), Profit: 2, Held: true},
+		{Name: "Downlink", Client: "acme", Resources: set.Of("antenna"), Profit: 3, Pinned: true},
+	}, AnyVersion)
+
+	tests := []struct {
+		name  string
+		query func(q *model.TaskQuery)
+		want  []uint64
+	}{
+		{"all", func(q *model.TaskQuery) {}, []uint64{1, 2, 3, 4}},
+		{"resource", func(q *model.TaskQuery) { q.Resources = []string{"disk"} }, []uint64{2, 3}},
+		{"resources", func(q *model.TaskQuery) { q.Resources = []string{"camera", "disk"} }, []uint64{2}},
+		{"name", func(q *model.TaskQuery) { q.Name = "capture" }, []uint64{1, 2}},
+		{"profit range", func(q *model.TaskQuery) { q.MinProfit, q.MaxProfit = 2, 2.5 }, []uint64{3}},
+		{"client", func(q *model.TaskQuery) { q.Clients = []string{"acme"} }, []uint64{1, 4}},
+		{"status", func(q *model.TaskQuery) { q.Statuses = []model.TaskStatus{model.TaskHeld, model.TaskPinned} }, []uint64{3, 4}},
+		{"profit", func(q *model.TaskQuery) { q.Sort = model.TaskSortProfit }, []uint64{2, 3, 1, 4}},
+		{"profit descending", func(q *model.TaskQuery) { q.Sort, q.Descending = model.TaskSortProfit, true }, []uint64{4, 1, 3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := model.AllTasksQuery()
+			tt.query(&query)
+			page, err := s.QueryTasks(query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := taskIDs(page.Tasks); !reflect.DeepEqual(got, tt.want) || page.Total != len(tt.want) {
+				t.Errorf("QueryTasks() = %v (total %d), want %v", got, page.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskService_QueryTasks_Pagination(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	s.AddTasks([]model.Task{{Profit: 2}, {Profit: 1}, {Profit: 2}, {Profit: 3}, {Profit: 1}}, AnyVersion)
+	query := model.AllTasksQuery()
+	query.Sort, query.Descending, query.Limit = model.TaskSortProfit, true, 2
+
+	var pages [][]uint64
+	for {
+		page, err := s.QueryTasks(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, taskIDs(page.Tasks))
+		if page.NextCursor == "" {
+			break
+		}
+		query.Cursor = page.NextCursor
+		if len(pages) == 1 {
+			// Tasks added or removed between pages don't shift them.
+			s.AddTasks([]model.Task{{Profit: 5}}, AnyVersion)
+		}
+	}
+	want := [][]uint64{{4, 3}, {1, 5}, {2}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+
+	query.Sort = model.TaskSortSubmittedAt
+	if _, err := s.QueryTasks(query); err == nil {
+		t.Errorf("QueryTasks() with a cursor of a different sort didn't fail")
+	}
+}
+
+func taskIDs(tasks []model.Task) []uint64 {
+	ids := make([]uint64, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"task_optimizer/internal/model"
+)
+
+var (
+	ErrUnknownResource       = errors.New("unknown resource")
+	ErrResourceNotFound      = errors.New("resource not found")
+	ErrResourceParentMissing = errors.New("parent resource not in the catalog")
+	ErrResourceHasChildren   = errors.New("resource has children")
+	ErrResourceInUse         = errors.New("resource claimed by queued tasks or templates")
+	ErrInvalidResourceName   = errors.New("invalid resource name")
+)
+
+// AddResources registers the resources in the catalog, replacing any resource
+// already registered with the same name. The parent of each resource must be
+// in the catalog or among the resources added.
+func (s *TaskService) AddResources(resources []model.Resource) error {
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+	added := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		added[resource.Name] = true
+	}
+	for _, resource := range resources {
+		if resource.Name == "" || strings.HasPrefix(resource.Name, ".") || strings.HasSuffix(resource.Name, ".") {
+			return fmt.Errorf("%w: %q", ErrInvalidResourceName, resource.Name)
+		}
+		parent := resource.Parent()
+		if _, ok := s.resources[parent]; parent != "" && !ok && !added[parent] {
+			return fmt.Errorf("%w: %s", ErrResourceParentMissing, resource.Name)
+		}
+	}
+
+	if s.resources == nil {
+		s.resources = make(model.ResourceCatalog, len(resources))
+	}
+	for _, resource := range resources {
+		s.resources[resource.Name] = resource
+	}
+	return nil
+}
+
+func (s *TaskService) ListResources() []model.Resource {
+	s.resourcesMu.RLock()
+	resources := make([]model.Resource, 0, len(s.resources))
+	for _, resource := range s.resources {
+		resources = append(resources, resource)
+	}
+	s.resourcesMu.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name < resources[j].Name
+	})
+	return resources
+}
+
+// RemoveResource removes the resource from the catalog. Resources with
+// children, or claimed by a queued task or a template, can't be removed.
+func (s *TaskService) RemoveResource(name string) error {
+	s.resourcesMu.Lock()
+	defer s.resourcesMu.Unlock()
+	if _, ok := s.resources[name]; !ok {
+		return ErrResourceNotFound
+	}
+	for _, resource := range s.resources {
+		if resource.Parent() == name {
+			return fmt.Errorf("%w: %s", ErrResourceHasChildren, name)
+		}
+	}
+	if err := s.checkResourceUnclaimed(name); err != nil {
+		return err
+	}
+	delete(s.resources, name)
+	return nil
+}
+
+// checkResourceUnclaimed returns an error naming a template or a queued task
+// that claims the resource, if there is one.
+func (s *TaskService) checkResourceUnclaimed(name string) error {
+	s.templatesMu.RLock()
+	defer s.templatesMu.RUnlock()
+	for _, template := range s.templates {
+		if template.Task.Resources.Contains(name) {
+			return fmt.Errorf("%w: %s is claimed by template %d (%s)", ErrResourceInUse, name, template.ID, template.Name)
+		}
+	}
+	s.tasksMu.RLock()
+	defer s.tasksMu.RUnlock()
+	for _, task := range s.tasks {
+		if task.Resources.Contains(name) {
+			return fmt.Errorf("%w: %s is claimed by task %d (%s)", ErrResourceInUse, name, task.ID, task.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateTask returns an error if the task has no valid priority or claims
+// a resource that is not in the catalog. Any resource is valid while the
+// catalog is empty.
+func (s *TaskService) ValidateTask(task model.Task) error {
+	if !task.Priority.Valid() {
+		return fmt.Errorf("%w: task %s has %s", ErrInvalidPriority, task.Name, task.Priority)
+	}
+	s.resourcesMu.RLock()
+	defer s.resourcesMu.RUnlock()
+	for resource := range task.Resources {
+		if err := s.resources.Validate(resource); err != nil {
+			return fmt.Errorf("%w: task %s claims %s", ErrUnknownResource, task.Name, resource)
+		}
+	}
+	return nil
+}
+
+// resourcePolicy returns the policy used to check the compatibility of the
+// tasks, with a snapshot of the catalog.
+func (s *TaskService) resourcePolicy() model.ResourcePolicy {
+	s.resourcesMu.RLock()
+	catalog := make(model.ResourceCatalog, len(s.resources))
+	for name, resource := range s.resources {
+		catalog[name] = resource
+	}
+	s.resourcesMu.RUnlock()
+
+	return model.ResourcePolicy{
+		Catalog:   catalog,
+		Conflicts: s.config.Conflicts,
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/schedule"
+	"testing"
+	"time"
+)
+
+func TestTaskService_RemoveResource(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	if err := s.AddResources([]model.Resource{{Name: "camera"}, {Name: "camera.optical"}, {Name: "antenna"}, {Name: "sensor"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddTasks([]model.Task{{Name: "capture", Resources: set.Of("camera.optical"), Profit: 1}}, AnyVersion); err != nil {
+		t.Fatal(err)
+	}
+	s.AddTaskTemplate(model.TaskTemplate{
+		Name:     "daily downlink",
+		Task:     model.Task{Name: "downlink", Resources: set.Of("antenna"), Profit: 1},
+		Schedule: schedule.Interval(24 * time.Hour),
+	})
+
+	tests := []struct {
+		name     string
+		resource string
+		want     error
+	}{
+		{"unknown", "disk", ErrResourceNotFound},
+		{"with children", "camera", ErrResourceHasChildren},
+		{"claimed by a task", "camera.optical", ErrResourceInUse},
+		{"claimed by a template", "antenna", ErrResourceInUse},
+		{"unclaimed", "sensor", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := s.RemoveResource(tt.resource); !errors.Is(err, tt.want) {
+				t.Errorf("RemoveResource(%q) error = %v, want %v", tt.resource, err, tt.want)
+			}
+		})
+	}
+
+	// Once executed the task no longer claims the resource.
+	if _, err := s.GetHigherProfitSubset(context.Background(), model.UnconstrainedPlanRequest()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveResource("camera.optical"); err != nil {
+		t.Errorf("RemoveResource() after the execution error = %v", err)
+	}
+}
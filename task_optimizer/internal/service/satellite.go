@@ -0,0 +1,47 @@
+package service
+
+import (
+	"sort"
+	"task_optimizer/internal/model"
+)
+
+// AddSatellites registers the satellites in the fleet, replacing any
+// satellite already registered with the same name.
+func (s *TaskService) AddSatellites(satellites []model.Satellite) {
+	s.satellitesMu.Lock()
+	if s.satellites == nil {
+		s.satellites = make(map[string]model.Satellite, len(satellites))
+	}
+	for _, satellite := range satellites {
+		s.satellites[satellite.Name] = satellite
+	}
+	s.metrics.FleetSize.Set(float64(len(s.satellites)))
+	s.satellitesMu.Unlock()
+}
+
+func (s *TaskService) ListSatellites() []model.Satellite {
+	s.satellitesMu.RLock()
+	satellites := make([]model.Satellite, 0, len(s.satellites))
+	for _, satellite := range s.satellites {
+		satellites = append(satellites, satellite)
+	}
+	s.satellitesMu.RUnlock()
+
+	sort.Slice(satellites, func(i, j int) bool {
+		return satellites[i].Name < satellites[j].Name
+	})
+	return satellites
+}
+
+// RemoveSatellite removes the satellite from the fleet and reports whether it
+// was registered.
+func (s *TaskService) RemoveSatellite(name string) bool {
+	s.satellitesMu.Lock()
+	defer s.satellitesMu.Unlock()
+	if _, ok := s.satellites[name]; !ok {
+		return false
+	}
+	delete(s.satellites, name)
+	s.metrics.FleetSize.Set(float64(len(s.satellites)))
+	return true
+}
@@ -1,66 +1,306 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
 	"sync"
 	"task_optimizer/internal/ds/graph"
 	"task_optimizer/internal/ds/set"
 	"task_optimizer/internal/ds/taskgraph"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/logging"
 	"task_optimizer/internal/metrics"
 	"task_optimizer/internal/model"
 	"time"
 )
 
+var (
+	// ErrTooManyTasks is returned when a request adds more tasks than
+	// Config.MaxTasksPerRequest.
+	ErrTooManyTasks = errors.New("too many tasks in the request")
+	// ErrVersionMismatch is returned when a request made for a version of
+	// the task list finds the list changed.
+	ErrVersionMismatch = errors.New("task list version mismatch")
+	ErrInvalidPriority = errors.New("invalid priority")
+)
+
+// AnyVersion is the version precondition met by every version of the task
+// list. Versions start at 1.
+const AnyVersion uint64 = 0
+
 type TaskService struct {
-	tasksMu sync.RWMutex
-	tasks   []model.Task
+	tasksMu    sync.RWMutex
+	tasks      []model.Task
+	lastTaskID uint64
+	// version is incremented on every change of the task list.
+	version uint64
+
+	satellitesMu sync.RWMutex
+	satellites   map[string]model.Satellite
+
+	templatesMu    sync.RWMutex
+	templates      map[uint64]model.TaskTemplate
+	lastTemplateID uint64
+
+	resourcesMu sync.RWMutex
+	resources   model.ResourceCatalog
+
+	outagesMu    sync.RWMutex
+	outages      map[uint64]model.Outage
+	lastOutageID uint64
 
+	webhooksMu       sync.RWMutex
+	webhooks         map[uint64]model.Webhook
+	lastWebhookID    uint64
+	deadLetters      []model.DeadLetter
+	lastDeadLetterID uint64
+
+	events *events.Bus
+
+	config  Config
 	metrics *metrics.TaskServiceMetrics
+	// now returns the current time, the reaper evicts the tasks expired at
+	// it. Tests replace it to move the clock.
+	now func() time.Time
 }
 
-func NewTaskService(taskServiceMetrics *metrics.TaskServiceMetrics) *TaskService {
+func NewTaskService(config Config, taskServiceMetrics *metrics.TaskServiceMetrics) *TaskService {
 	return &TaskService{
+		version: 1,
+		events:  events.NewBus(config.EventBufferSize),
+		config:  config,
 		metrics: taskServiceMetrics,
+		now:     time.Now,
 	}
 }
 
-func (s *TaskService) AddTasks(tasks []model.Task) {
+// AddTasks adds the tasks to the list, or none of them if any is invalid
+// under ValidateTask, they are too many or the list is not at ifVersion,
+// unless it's AnyVersion. It returns the new version of the list.
+func (s *TaskService) AddTasks(tasks []model.Task, ifVersion uint64) (uint64, error) {
+	if err := s.ValidateTaskCount(len(tasks)); err != nil {
+		return 0, err
+	}
+	for _, task := range tasks {
+		if err := s.ValidateTask(task); err != nil {
+			return 0, err
+		}
+	}
+	submittedAt := time.Now()
+	s.tasksMu.Lock()
+	defer s.tasksMu.Unlock()
+	if err := s.checkVersion(ifVersion); err != nil {
+		return 0, err
+	}
+	s.addTasks(tasks, submittedAt)
+	return s.version, nil
+}
+
+// checkVersion checks that the list is at ifVersion, unless it's AnyVersion.
+// The caller must hold tasksMu.
+func (s *TaskService) checkVersion(ifVersion uint64) error {
+	if ifVersion != AnyVersion && ifVersion != s.version {
+		return fmt.Errorf("%w: the list is at version %d, not %d", ErrVersionMismatch, s.version, ifVersion)
+	}
+	return nil
+}
+
+// ValidateTaskCount checks that a request can add count tasks.
+func (s *TaskService) ValidateTaskCount(count int) error {
+	if s.config.MaxTasksPerRequest > 0 && count > s.config.MaxTasksPerRequest {
+		return fmt.Errorf("%w: more than %d", ErrTooManyTasks, s.config.MaxTasksPerRequest)
+	}
+	return nil
+}
+
+// addTasks appends the tasks to the list assigning their ID and submission
+// time. The caller must hold tasksMu.
+func (s *TaskService) addTasks(tasks []model.Task, submittedAt time.Time) {
 	if s.tasks == nil {
 		s.tasks = make([]model.Task, 0, len(tasks))
 	}
-	s.tasksMu.Lock()
 	for _, task := range tasks {
+		s.lastTaskID++
+		task.ID = s.lastTaskID
+		task.SubmittedAt = submittedAt
 		s.tasks = append(s.tasks, task)
+		s.events.Publish(events.TaskAdded, task)
+	}
+	if len(tasks) > 0 {
+		s.version++
 	}
 	s.metrics.TaskListSize.Set(float64(len(s.tasks)))
-	s.tasksMu.Unlock()
 }
 
 func (s *TaskService) ListAllTasks() []model.Task {
-	s.tasksMu.RLock()
-	tasks := s.tasks[:]
-	s.tasksMu.RUnlock()
+	tasks, _ := s.Snapshot()
 	return tasks
 }
 
-func (s *TaskService) GetHigherProfitSubset() []model.Task {
+// Snapshot returns the tasks of the list and the version of the list they
+// were read at.
+func (s *TaskService) Snapshot() ([]model.Task, uint64) {
+	s.tasksMu.RLock()
+	defer s.tasksMu.RUnlock()
+	return s.tasks[:], s.version
+}
+
+// GetHigherProfitSubset removes from the list the subset of compatible tasks
+// that maximizes the effective profit, minus the penalties of the resources
+// shared, within the energy budget and fairness policy of the request, and
+// returns it. Pinned tasks are always part of the subset and held tasks never
+// are. Critical tasks take precedence over profit, and the profit of waiting
+// tasks is boosted by their priority class. Expired tasks are evicted before
+// planning, and tasks needing a resource under an outage are deferred. When
+// satellites are registered the tasks are assigned to the satellites
+// maximizing the fleet-wide profit. The execution fails with
+// ErrVersionMismatch if the list is not at the IfVersion of the request, and
+// stops when ctx is done leaving the list as it was.
+func (s *TaskService) GetHigherProfitSubset(ctx context.Context, request model.PlanRequest) (model.Plan, error) {
+	s.events.Publish(events.ExecutionStarted, request)
+	plan, err := s.plan(ctx, request)
+	if err != nil {
+		s.events.Publish(events.ExecutionFailed, events.ExecutionFailure{Request: request, Err: err})
+		return model.Plan{}, err
+	}
+	s.events.Publish(events.ExecutionCompleted, plan)
+	return plan, nil
+}
+
+// plan computes the plan of GetHigherProfitSubset and removes its tasks from
+// the list.
+func (s *TaskService) plan(ctx context.Context, request model.PlanRequest) (model.Plan, error) {
 	startTime := time.Now()
+	satellites := s.ListSatellites()
+	policy := s.resourcePolicy()
+	outages := s.activeOutages(startTime)
 
 	s.tasksMu.Lock()
+	defer s.tasksMu.Unlock()
+	if err := s.checkVersion(request.IfVersion); err != nil {
+		return model.Plan{}, err
+	}
+	s.evictExpiredTasks(startTime)
 	s.metrics.InputTaskListSize.Observe(float64(len(s.tasks)))
-	compatibilityGraph := taskgraph.BuildCompatibilityGraph(s.tasks)
-	bronKerboschStartTime := time.Now()
-	taskNodesSubset, _ := graph.BronKerbosch(
-		set.Empty[int](),
-		compatibilityGraph.GetNodes(),
-		set.Empty[int](),
-		compatibilityGraph,
-	)
-	s.metrics.BronKerboschTime.Observe(time.Since(bronKerboschStartTime).Seconds())
-	remainingNodes := compatibilityGraph.GetNodes().Difference(taskNodesSubset)
-	s.tasks = compatibilityGraph.GetTasksFromNodes(remainingNodes)
+	candidateTasks := make([]model.Task, 0, len(s.tasks))
+	var pinnedTasks []model.Task
+	var deferrals []model.Deferral
+	for _, task := range s.tasks {
+		if task.Held {
+			continue
+		}
+		if deferral, deferred := outageDeferral(task, outages, policy.Catalog); deferred {
+			deferrals = append(deferrals, deferral)
+			continue
+		}
+		candidateTasks = append(candidateTasks, task)
+		if task.Pinned {
+			pinnedTasks = append(pinnedTasks, task)
+		}
+	}
+	var compatibilityGraph taskgraph.TaskCompatibilityGraph
+	if len(satellites) > 0 {
+		compatibilityGraph = taskgraph.BuildFleetCompatibilityGraph(candidateTasks, satellites, policy)
+	} else {
+		compatibilityGraph = taskgraph.BuildCompatibilityGraph(candidateTasks, policy)
+	}
+	compatibilityGraph = compatibilityGraph.WithWeights(s.taskWeight(candidateTasks, compatibilityGraph.PenaltyTotal(), startTime))
+	groups, err := pinnedGroups(compatibilityGraph, pinnedTasks)
+	if err != nil {
+		return model.Plan{}, err
+	}
+
+	taskNodesSubset := set.Empty[int]()
+	found, fairnessMet := false, true
+	searchStartTime := time.Now()
+	unconstrained := math.IsInf(request.EnergyBudget, 1) && request.Fairness.Policy == model.FairnessNone
+	if unconstrained && !compatibilityGraph.HasPenalties() && len(pinnedTasks) == 0 && s.config.Solver != SolverBranchAndBound {
+		taskNodesSubset, _ = graph.BronKerbosch(ctx, set.Empty[int](), compatibilityGraph.GetNodes(), set.Empty[int](), compatibilityGraph)
+		found = true
+		s.metrics.BronKerboschTime.Observe(time.Since(searchStartTime).Seconds())
+	} else {
+		objective := s.newPlanObjective(compatibilityGraph, request, startTime)
+		expired := false
+		search := func() {
+			var searchObjective graph.Objective = cancelableObjective{Objective: objective, ctx: ctx}
+			if s.config.SolverTimeout <= 0 {
+				taskNodesSubset, found = maxScoreClique(compatibilityGraph, groups, searchObjective)
+				return
+			}
+			deadline := &graph.DeadlineObjective{Objective: searchObjective, Deadline: searchStartTime.Add(s.config.SolverTimeout)}
+			taskNodesSubset, found = maxScoreClique(compatibilityGraph, groups, deadline)
+			expired = expired || deadline.Expired
+		}
+		search()
+		if !found && objective.fairness.Policy != model.FairnessNone {
+			// The minimum share policy can leave no valid plan, fall back to
+			// a plan within the budget alone.
+			fairnessMet = false
+			objective.fairness = model.Fairness{}
+			search()
+		}
+		s.metrics.ConstrainedSearchTime.Observe(time.Since(searchStartTime).Seconds())
+		if expired {
+			logging.FromContext(ctx).Warn().Dur("timeout", s.config.SolverTimeout).Msg("solver timed out, the plan may not be the best one")
+			s.metrics.SolverTimeouts.Inc()
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		// No task was removed yet, so the list is left as it was.
+		return model.Plan{}, fmt.Errorf("execution canceled: %w", err)
+	}
+	if !found {
+		return model.Plan{}, s.pinnedError(compatibilityGraph, pinnedTasks, groups, startTime)
+	}
+
+	selectedTasks := set.Empty[uint64]()
+	for taskIdx := range compatibilityGraph.GetTaskIndexesFromNodes(taskNodesSubset) {
+		selectedTasks.Add(candidateTasks[taskIdx].ID)
+	}
+	remainingTasks := make([]model.Task, 0, len(s.tasks)-len(selectedTasks))
+	for _, task := range s.tasks {
+		if !selectedTasks.Contains(task.ID) {
+			remainingTasks = append(remainingTasks, task)
+			continue
+		}
+		s.events.Publish(events.TaskRemoved, events.TaskRemoval{Task: task, Reason: events.RemovalExecuted})
+	}
+	if len(selectedTasks) > 0 {
+		s.version++
+	}
+	s.tasks = remainingTasks
 	s.metrics.TaskListSize.Set(float64(len(s.tasks)))
-	s.tasksMu.Unlock()
+
+	plan := model.Plan{
+		Version:      s.version,
+		PlannedAt:    startTime,
+		Assignments:  compatibilityGraph.GetAssignmentsFromNodes(taskNodesSubset),
+		EnergyBudget: request.EnergyBudget,
+		Fairness:     request.Fairness,
+		FairnessMet:  fairnessMet,
+		Penalties:    planPenalties(compatibilityGraph, taskNodesSubset),
+		Deferred:     deferrals,
+	}
+	for _, assignment := range plan.Assignments {
+		plan.EnergyUsed += assignment.Task.Energy
+	}
+	for _, share := range plan.ClientShares() {
+		client := s.metrics.ClientLabel(share.Client)
+		// Pinned tasks can have a negative profit, and counters can't
+		// decrease.
+		s.metrics.ClientSelectedProfit.WithLabelValues(client).Add(math.Max(0, share.Profit))
+		s.metrics.ClientSelectedTasks.WithLabelValues(client).Add(float64(share.Tasks))
+	}
 
 	s.metrics.ProcessingTime.Observe(time.Since(startTime).Seconds())
-	return compatibilityGraph.GetTasksFromNodes(taskNodesSubset)
+	return plan, nil
+}
+
+// maxScoreClique returns the clique with maximum score that has a node of
+// each of the pinned groups, and whether there is one.
+func maxScoreClique(compatibilityGraph taskgraph.TaskCompatibilityGraph, groups []set.Set[int], objective graph.Objective) (set.Set[int], bool) {
+	clique, _, found := graph.MaxScoreCliqueCovering(set.Empty[int](), compatibilityGraph.GetNodes(), groups, compatibilityGraph, objective)
+	return clique, found
 }
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/model"
+	"testing"
+	"time"
+)
+
+func TestTaskService_Version(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	_, version := s.Snapshot()
+
+	added, err := s.AddTasks([]model.Task{
+		{Name: "capture", Resources: set.Of("camera"), Profit: 2},
+		{Name: "downlink", Resources: set.Of("antenna"), Profit: 1, ExpiresAt: time.Now().Add(time.Hour)},
+	}, version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added <= version {
+		t.Errorf("AddTasks() version = %d, want more than %d", added, version)
+	}
+	if _, err := s.AddTasks([]model.Task{{Name: "late"}}, version); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("AddTasks() at a stale version error = %v, want %v", err, ErrVersionMismatch)
+	}
+	if _, err := s.GetHigherProfitSubset(context.Background(), model.PlanRequest{EnergyBudget: 10, IfVersion: version}); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("GetHigherProfitSubset() at a stale version error = %v, want %v", err, ErrVersionMismatch)
+	}
+	if tasks := s.ListAllTasks(); len(tasks) != 2 {
+		t.Fatalf("tasks after failed preconditions = %d, want 2", len(tasks))
+	}
+
+	held := true
+	_, overridden, err := s.SetTaskOverrides(2, nil, &held, added)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if overridden <= added {
+		t.Errorf("SetTaskOverrides() version = %d, want more than %d", overridden, added)
+	}
+	plan, err := s.GetHigherProfitSubset(context.Background(), model.PlanRequest{EnergyBudget: 10, IfVersion: overridden})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, current := s.Snapshot(); plan.Version <= overridden || plan.Version != current {
+		t.Errorf("plan version = %d, list version = %d, want the same and more than %d", plan.Version, current, overridden)
+	}
+
+	// Executions and expirations that remove no task leave the version.
+	_, version = s.Snapshot()
+	if _, err := s.GetHigherProfitSubset(context.Background(), model.PlanRequest{EnergyBudget: 10}); err != nil {
+		t.Fatal(err)
+	}
+	s.tasksMu.Lock()
+	s.evictExpiredTasks(time.Now())
+	s.tasksMu.Unlock()
+	if _, current := s.Snapshot(); current != version {
+		t.Errorf("version = %d after no change, want %d", current, version)
+	}
+	s.tasksMu.Lock()
+	s.evictExpiredTasks(time.Now().Add(2 * time.Hour))
+	s.tasksMu.Unlock()
+	if _, current := s.Snapshot(); current == version {
+		t.Errorf("version unchanged after evicting a task")
+	}
+}
+
+func TestTaskService_Solver(t *testing.T) {
+	tasks := []model.Task{
+		{Name: "optical", Resources: set.Of("camera"), Profit: 3},
+		{Name: "sar", Resources: set.Of("camera", "disk"), Profit: 4},
+		{Name: "clean", Resources: set.Of("disk"), Profit: 2},
+		{Name: "downlink", Resources: set.Of("antenna"), Profit: 1},
+	}
+	tests := []struct {
+		name               string
+		config             func(config *Config)
+		minTasks, maxTasks int
+	}{
+		{"auto", func(config *Config) {}, 3, 3},
+		{"branch and bound", func(config *Config) { config.Solver = SolverBranchAndBound }, 3, 3},
+		// Out of time the search completes a plan greedily, sar and downlink
+		// or the best one.
+		{"timeout", func(config *Config) {
+			config.Solver, config.SolverTimeout = SolverBranchAndBound, time.Nanosecond
+		}, 2, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			tt.config(&config)
+			s := NewTaskService(config, taskServiceMetrics)
+			s.AddTasks(tasks, AnyVersion)
+			plan, err := s.GetHigherProfitSubset(context.Background(), model.UnconstrainedPlanRequest())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(plan.Assignments); got < tt.minTasks || got > tt.maxTasks {
+				t.Errorf("plan has %d tasks, want between %d and %d", got, tt.minTasks, tt.maxTasks)
+			}
+		})
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_Canceled(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	s.AddTasks([]model.Task{
+		{Name: "capture", Resources: set.Of("camera"), Profit: 2, Energy: 1},
+		{Name: "downlink", Resources: set.Of("antenna"), Profit: 1, Energy: 1},
+	}, AnyVersion)
+	_, version := s.Snapshot()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, request := range []model.PlanRequest{model.UnconstrainedPlanRequest(), {EnergyBudget: 1}} {
+		if _, err := s.GetHigherProfitSubset(ctx, request); !errors.Is(err, context.Canceled) {
+			t.Errorf("GetHigherProfitSubset() error = %v, want %v", err, context.Canceled)
+		}
+	}
+	if tasks, current := s.Snapshot(); len(tasks) != 2 || current != version {
+		t.Errorf("after canceled executions %d tasks at version %d, want 2 at version %d", len(tasks), current, version)
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_CanceledSearch(t *testing.T) {
+	// Tasks that are all compatible take Bron-Kerbosch exponential time.
+	tasks := make([]model.Task, 60)
+	for i := range tasks {
+		tasks[i] = model.Task{Name: fmt.Sprintf("task-%d", i), Resources: set.Of(fmt.Sprintf("resource-%d", i)), Profit: 1}
+	}
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	s.AddTasks(tasks, AnyVersion)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.GetHigherProfitSubset(ctx, model.UnconstrainedPlanRequest())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("GetHigherProfitSubset() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetHigherProfitSubset() didn't stop when ctx was done")
+	}
+	if tasks, _ := s.Snapshot(); len(tasks) != 60 {
+		t.Errorf("%d tasks after a canceled execution, want 60", len(tasks))
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_PinnedWithoutProfit(t *testing.T) {
+	for _, profit := range []float64{0, -1} {
+		s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+		s.AddTasks([]model.Task{
+			{Name: "calibration", Resources: set.Of("camera"), Profit: profit, Priority: model.PriorityStandard, Pinned: true},
+			{Name: "downlink", Resources: set.Of("antenna"), Priority: model.PriorityStandard},
+		}, AnyVersion)
+
+		plan, err := s.GetHigherProfitSubset(context.Background(), model.UnconstrainedPlanRequest())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pinned := false
+		for _, assignment := range plan.Assignments {
+			pinned = pinned || assignment.Task.Name == "calibration"
+		}
+		if !pinned {
+			t.Errorf("plan %v doesn't have the pinned task of profit %v", plan.Assignments, profit)
+		}
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_CriticalOverPenalties(t *testing.T) {
+	requests := map[string]model.PlanRequest{
+		"unconstrained": model.UnconstrainedPlanRequest(),
+		"proportional":  {EnergyBudget: math.Inf(1), Fairness: model.Fairness{Policy: model.FairnessProportional}},
+	}
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Conflicts = model.ConflictModel{"camera": 100}
+			s := NewTaskService(config, taskServiceMetrics)
+			// Both critical tasks share the camera paying a penalty bigger
+			// than the profit of every task, and scan conflicts with sar.
+			s.AddTasks([]model.Task{
+				{Name: "optical", Resources: set.Of("camera"), Profit: 1, Priority: model.PriorityCritical},
+				{Name: "scan", Resources: set.Of("camera", "disk"), Profit: 1, Priority: model.PriorityCritical},
+				{Name: "sar", Resources: set.Of("disk"), Profit: 5, Priority: model.PriorityStandard},
+			}, AnyVersion)
+
+			plan, err := s.GetHigherProfitSubset(context.Background(), request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			selected := make(map[string]bool)
+			for _, assignment := range plan.Assignments {
+				selected[assignment.Task.Name] = true
+			}
+			if len(selected) != 2 || !selected["optical"] || !selected["scan"] {
+				t.Errorf("plan selected %v, want optical and scan", selected)
+			}
+		})
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_PinnedAndHeld(t *testing.T) {
+	fleets := map[string][]model.Satellite{
+		"no fleet": nil,
+		"fleet": {
+			{Name: "sat-1", Resources: set.Of("camera", "sensor")},
+			{Name: "sat-2", Resources: set.Of("camera")},
+		},
+	}
+	requests := map[string]model.PlanRequest{
+		"unconstrained": model.UnconstrainedPlanRequest(),
+		"budget":        {EnergyBudget: 10},
+	}
+	for fleetName, satellites := range fleets {
+		for requestName, request := range requests {
+			t.Run(fleetName+"/"+requestName, func(t *testing.T) {
+				s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+				s.AddSatellites(satellites)
+				// Without the overrides both captures and the downlink would
+				// take the cameras instead of the calibration.
+				s.AddTasks([]model.Task{
+					{Name: "capture-1", Resources: set.Of("camera"), Profit: 10, Priority: model.PriorityStandard, Satellites: set.Of("sat-1")},
+					{Name: "capture-2", Resources: set.Of("camera"), Profit: 10, Priority: model.PriorityStandard, Satellites: set.Of("sat-2")},
+					{Name: "calibration", Resources: set.Of("camera"), Profit: 1, Priority: model.PriorityStandard, Pinned: true},
+					{Name: "downlink", Resources: set.Of("camera"), Profit: 50, Priority: model.PriorityStandard, Held: true},
+					{Name: "survey", Resources: set.Of("sensor"), Profit: 2, Priority: model.PriorityStandard},
+				}, AnyVersion)
+
+				plan, err := s.GetHigherProfitSubset(context.Background(), request)
+				if err != nil {
+					t.Fatal(err)
+				}
+				selected := make(map[string]int)
+				for _, assignment := range plan.Assignments {
+					selected[assignment.Task.Name]++
+				}
+				if selected["calibration"] != 1 {
+					t.Errorf("plan selected %v, want the pinned calibration once", selected)
+				}
+				if selected["downlink"] != 0 {
+					t.Errorf("plan selected %v, want no held downlink", selected)
+				}
+				if selected["survey"] != 1 {
+					t.Errorf("plan selected %v, want the survey completing the plan", selected)
+				}
+			})
+		}
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_PinnedInLargeFleet(t *testing.T) {
+	// Picking the satellite of each pinned task in the search keeps it from
+	// enumerating every assignment of the pinned tasks, 10⁸ here.
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	var satellites []model.Satellite
+	for i := 0; i < 10; i++ {
+		satellites = append(satellites, model.Satellite{Name: fmt.Sprintf("sat-%d", i), Resources: set.Of("camera", "antenna")})
+	}
+	s.AddSatellites(satellites)
+	var tasks []model.Task
+	for i := 0; i < 8; i++ {
+		tasks = append(tasks, model.Task{Name: fmt.Sprintf("pinned-%d", i), Resources: set.Of("camera"), Profit: 1, Priority: model.PriorityStandard, Pinned: true})
+		tasks = append(tasks, model.Task{Name: fmt.Sprintf("downlink-%d", i), Resources: set.Of("antenna"), Profit: 1, Priority: model.PriorityStandard})
+	}
+	s.AddTasks(tasks, AnyVersion)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	plan, err := s.GetHigherProfitSubset(ctx, model.PlanRequest{EnergyBudget: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Assignments) != len(tasks) {
+		t.Errorf("plan has %d tasks, want all %d", len(plan.Assignments), len(tasks))
+	}
+}
+
+func TestTaskService_GetHigherProfitSubset_PinnedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []model.Task
+		request model.PlanRequest
+		wantErr error
+	}{
+		{
+			name: "conflict",
+			tasks: []model.Task{
+				{Name: "calibration", Resources: set.Of("camera"), Pinned: true},
+				{Name: "capture", Resources: set.Of("camera"), Pinned: true},
+			},
+			request: model.UnconstrainedPlanRequest(),
+			wantErr: ErrPinnedConflict,
+		},
+		{
+			name: "over budget",
+			tasks: []model.Task{
+				{Name: "calibration", Resources: set.Of("camera"), Energy: 5, Pinned: true},
+				{Name: "downlink", Resources: set.Of("antenna"), Energy: 5, Pinned: true},
+			},
+			request: model.PlanRequest{EnergyBudget: 8},
+			wantErr: ErrPinnedOverBudget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+			s.AddTasks(tt.tasks, AnyVersion)
+			if _, err := s.GetHigherProfitSubset(context.Background(), tt.request); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetHigherProfitSubset() error = %v, want %v", err, tt.wantErr)
+			}
+			if tasks := s.ListAllTasks(); len(tasks) != len(tt.tasks) {
+				t.Errorf("tasks after the failed execution = %d, want %d", len(tasks), len(tt.tasks))
+			}
+		})
+	}
+}
+
+func TestTaskService_AddTasks_Priority(t *testing.T) {
+	s := NewTaskService(DefaultConfig(), taskServiceMetrics)
+	if _, err := s.AddTasks([]model.Task{{Name: "capture", Priority: model.Priority(7)}}, AnyVersion); !errors.Is(err, ErrInvalidPriority) {
+		t.Errorf("AddTasks() error = %v, want %v", err, ErrInvalidPriority)
+	}
+	if _, err := s.AddTasks([]model.Task{{Name: "capture"}}, AnyVersion); err != nil {
+		t.Fatal(err)
+	}
+	if priority := s.ListAllTasks()[0].Priority; priority != model.PriorityStandard {
+		t.Errorf("priority of a task without one = %s, want %s", priority, model.PriorityStandard)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"task_optimizer/internal/model"
+	"time"
+)
+
+// AddTaskTemplate registers the template assigning its ID and first
+// activation, and returns it.
+func (s *TaskService) AddTaskTemplate(template model.TaskTemplate) model.TaskTemplate {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	if s.templates == nil {
+		s.templates = make(map[uint64]model.TaskTemplate)
+	}
+	s.lastTemplateID++
+	template.ID = s.lastTemplateID
+	template.NextRun = template.Schedule.Next(time.Now())
+	s.templates[template.ID] = template
+	return template
+}
+
+func (s *TaskService) ListTaskTemplates() []model.TaskTemplate {
+	s.templatesMu.RLock()
+	templates := make([]model.TaskTemplate, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	s.templatesMu.RUnlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+	return templates
+}
+
+func (s *TaskService) GetTaskTemplate(id uint64) (model.TaskTemplate, bool) {
+	s.templatesMu.RLock()
+	defer s.templatesMu.RUnlock()
+	template, ok := s.templates[id]
+	return template, ok
+}
+
+// UpdateTaskTemplate replaces the template with the given ID, rescheduling
+// its next activation, and reports whether it exists.
+func (s *TaskService) UpdateTaskTemplate(id uint64, template model.TaskTemplate) (model.TaskTemplate, bool) {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	if _, ok := s.templates[id]; !ok {
+		return model.TaskTemplate{}, false
+	}
+	template.ID = id
+	template.NextRun = template.Schedule.Next(time.Now())
+	s.templates[id] = template
+	return template, true
+}
+
+func (s *TaskService) RemoveTaskTemplate(id uint64) bool {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	if _, ok := s.templates[id]; !ok {
+		return false
+	}
+	delete(s.templates, id)
+	return true
+}
+
+// RunScheduler enqueues the due task templates every SchedulerInterval until
+// ctx is done.
+func (s *TaskService) RunScheduler(ctx context.Context) {
+	ticker := time.NewTicker(s.config.SchedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.enqueueDueTemplates(now)
+		}
+	}
+}
+
+// enqueueDueTemplates adds a new instance of every template due at now,
+// unless the previous instance of the template is still pending.
+func (s *TaskService) enqueueDueTemplates(now time.Time) {
+	s.templatesMu.Lock()
+	defer s.templatesMu.Unlock()
+	s.tasksMu.Lock()
+	defer s.tasksMu.Unlock()
+
+	pendingTemplates := make(map[uint64]bool)
+	for _, task := range s.tasks {
+		if task.TemplateID != 0 {
+			pendingTemplates[task.TemplateID] = true
+		}
+	}
+	for id, template := range s.templates {
+		if template.NextRun.IsZero() || template.NextRun.After(now) {
+			continue
+		}
+		template.NextRun = template.Schedule.Next(now)
+		s.templates[id] = template
+
+		logger := log.With().Uint64("template_id", id).Str("template_name", template.Name).Logger()
+		if pendingTemplates[id] {
+			logger.Info().Msg("template skipped, previous instance still pending")
+			continue
+		}
+		s.addTasks([]model.Task{template.NewInstance(now)}, now)
+		s.metrics.TemplateTasks.Inc()
+		logger.Info().Uint64("task_id", s.lastTaskID).Msg("template task enqueued")
+	}
+}
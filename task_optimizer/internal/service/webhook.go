@@ -0,0 +1,69 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"task_optimizer/internal/model"
+)
+
+// maxDeadLetters is the number of dead letters kept, the oldest are dropped.
+const maxDeadLetters = 1000
+
+// AddWebhook registers the webhook assigning its ID, and returns it.
+func (s *TaskService) AddWebhook(webhook model.Webhook) (model.Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return model.Webhook{}, err
+	}
+	s.webhooksMu.Lock()
+	defer s.webhooksMu.Unlock()
+	if s.webhooks == nil {
+		s.webhooks = make(map[uint64]model.Webhook)
+	}
+	s.lastWebhookID++
+	webhook.ID = s.lastWebhookID
+	s.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (s *TaskService) ListWebhooks() []model.Webhook {
+	s.webhooksMu.RLock()
+	webhooks := make([]model.Webhook, 0, len(s.webhooks))
+	for _, webhook := range s.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	s.webhooksMu.RUnlock()
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].ID < webhooks[j].ID
+	})
+	return webhooks
+}
+
+func (s *TaskService) RemoveWebhook(id uint64) bool {
+	s.webhooksMu.Lock()
+	defer s.webhooksMu.Unlock()
+	if _, ok := s.webhooks[id]; !ok {
+		return false
+	}
+	delete(s.webhooks, id)
+	return true
+}
+
+// AddDeadLetter records a failed webhook delivery assigning its ID.
+func (s *TaskService) AddDeadLetter(deadLetter model.DeadLetter) {
+	s.webhooksMu.Lock()
+	defer s.webhooksMu.Unlock()
+	s.lastDeadLetterID++
+	deadLetter.ID = s.lastDeadLetterID
+	s.deadLetters = append(s.deadLetters, deadLetter)
+	if len(s.deadLetters) > maxDeadLetters {
+		s.deadLetters = slices.Clone(s.deadLetters[len(s.deadLetters)-maxDeadLetters:])
+	}
+}
+
+// ListDeadLetters returns the failed webhook deliveries, oldest first.
+func (s *TaskService) ListDeadLetters() []model.DeadLetter {
+	s.webhooksMu.RLock()
+	defer s.webhooksMu.RUnlock()
+	return slices.Clone(s.deadLetters)
+}
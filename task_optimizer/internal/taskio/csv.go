@@ -0,0 +1,313 @@
+package taskio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"task_optimizer/internal/dto"
+	"time"
+)
+
+// ListSeparator separates the items of the list columns, like resources.
+const ListSeparator = ";"
+
+// column is a CSV column of a task field. Read-only fields have no set
+// function and are ignored when decoding.
+type column struct {
+	name string
+	get  func(task dto.Task) string
+	set  func(task *dto.Task, value string) error
+}
+
+// columns are the CSV columns in the order they are encoded. Decay fields are
+// flattened in the decay* columns.
+var columns = []column{
+	{"id", func(t dto.Task) string { return formatUint(t.ID) }, nil},
+	{"name", func(t dto.Task) string { return t.Name }, func(t *dto.Task, v string) error {
+		t.Name = v
+		return nil
+	}},
+	{"client", func(t dto.Task) string { return t.Client }, func(t *dto.Task, v string) error {
+		t.Client = v
+		return nil
+	}},
+	{"resources", func(t dto.Task) string { return strings.Join(t.Resources, ListSeparator) }, func(t *dto.Task, v string) error {
+		t.Resources = parseList(v)
+		return nil
+	}},
+	{"profit", func(t dto.Task) string { return formatFloat(t.Profit) }, func(t *dto.Task, v string) error {
+		return parseFloat(v, &t.Profit)
+	}},
+	{"effectiveProfit", func(t dto.Task) string { return formatFloat(t.EffectiveProfit) }, nil},
+	{"decayType", func(t dto.Task) string { return decayField(t, func(d dto.Decay) string { return d.Type }) }, func(t *dto.Task, v string) error {
+		decayOf(t, v).Type = v
+		return nil
+	}},
+	{"decayRatePerHour", func(t dto.Task) string {
+		return decayField(t, func(d dto.Decay) string { return formatFloat(d.RatePerHour) })
+	}, func(t *dto.Task, v string) error {
+		return parseFloat(v, &decayOf(t, v).RatePerHour)
+	}},
+	{"decayHalfLife", func(t dto.Task) string { return decayField(t, func(d dto.Decay) string { return d.HalfLife }) }, func(t *dto.Task, v string) error {
+		decayOf(t, v).HalfLife = v
+		return nil
+	}},
+	{"decayAfter", func(t dto.Task) string { return decayField(t, func(d dto.Decay) string { return d.After }) }, func(t *dto.Task, v string) error {
+		decayOf(t, v).After = v
+		return nil
+	}},
+	{"decayFactor", func(t dto.Task) string {
+		return decayField(t, func(d dto.Decay) string { return formatFloat(d.Factor) })
+	}, func(t *dto.Task, v string) error {
+		return parseFloat(v, &decayOf(t, v).Factor)
+	}},
+	{"satellites", func(t dto.Task) string { return strings.Join(t.Satellites, ListSeparator) }, func(t *dto.Task, v string) error {
+		t.Satellites = parseList(v)
+		return nil
+	}},
+	{"energy", func(t dto.Task) string { return formatFloat(t.Energy) }, func(t *dto.Task, v string) error {
+		return parseFloat(v, &t.Energy)
+	}},
+	{"priority", func(t dto.Task) string { return t.Priority }, func(t *dto.Task, v string) error {
+		t.Priority = v
+		return nil
+	}},
+	{"submittedAt", func(t dto.Task) string { return formatTime(&t.SubmittedAt) }, nil},
+	{"expiresAt", func(t dto.Task) string { return formatTime(t.ExpiresAt) }, func(t *dto.Task, v string) error {
+		if v == "" {
+			return nil
+		}
+		expiresAt, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return err
+		}
+		t.ExpiresAt = &expiresAt
+		return nil
+	}},
+	{"pinned", func(t dto.Task) string { return strconv.FormatBool(t.Pinned) }, func(t *dto.Task, v string) error {
+		return parseBool(v, &t.Pinned)
+	}},
+	{"held", func(t dto.Task) string { return strconv.FormatBool(t.Held) }, func(t *dto.Task, v string) error {
+		return parseBool(v, &t.Held)
+	}},
+	{"status", func(t dto.Task) string { return t.Status }, nil},
+	{"templateId", func(t dto.Task) string { return formatUint(t.TemplateID) }, nil},
+	{"submittedBy", func(t dto.Task) string { return t.SubmittedBy }, nil},
+}
+
+// requiredColumns are the columns that the header of a CSV stream must have.
+var requiredColumns = []string{"name", "resources", "profit"}
+
+// CSVDecoder reads tasks from CSV with a header row naming the columns, in
+// any order. Missing optional columns are left unset, and empty cells too.
+type CSVDecoder struct {
+	reader *csv.Reader
+	// header holds the column of each field, nil until the header is read.
+	header []*column
+	line   int
+}
+
+func NewCSVDecoder(r io.Reader) *CSVDecoder {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	return &CSVDecoder{reader: reader}
+}
+
+// Decode reads the task of the next record. An invalid header can't be
+// recovered from and is returned as a plain error.
+func (d *CSVDecoder) Decode() (dto.Task, error) {
+	if d.header == nil {
+		if err := d.readHeader(); err != nil {
+			return dto.Task{}, err
+		}
+	}
+	record, err := d.reader.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			d.line = parseErr.StartLine
+			return dto.Task{}, &RecordError{Line: d.line, Err: parseErr.Err}
+		}
+		return dto.Task{}, err
+	}
+	d.line, _ = d.reader.FieldPos(0)
+	if len(record) != len(d.header) {
+		return dto.Task{}, &RecordError{Line: d.line, Err: fmt.Errorf("got %d fields, want %d", len(record), len(d.header))}
+	}
+	var task dto.Task
+	for i, value := range record {
+		column := d.header[i]
+		if column.set == nil {
+			continue
+		}
+		if err := column.set(&task, strings.TrimSpace(value)); err != nil {
+			return dto.Task{}, &RecordError{Line: d.line, Err: fmt.Errorf("column %s: %w", column.name, err)}
+		}
+	}
+	return task, nil
+}
+
+func (d *CSVDecoder) Line() int {
+	return d.line
+}
+
+func (d *CSVDecoder) readHeader() error {
+	record, err := d.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return errors.New("missing CSV header")
+	}
+	if err != nil {
+		return fmt.Errorf("invalid CSV header: %w", err)
+	}
+	header := make([]*column, 0, len(record))
+	seen := make(map[string]bool, len(record))
+	for _, name := range record {
+		name = strings.TrimSpace(name)
+		index := columnIndex(name)
+		if index < 0 {
+			return fmt.Errorf("unknown CSV column %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicated CSV column %q", name)
+		}
+		seen[name] = true
+		header = append(header, &columns[index])
+	}
+	for _, name := range requiredColumns {
+		if !seen[name] {
+			return fmt.Errorf("missing CSV column %q", name)
+		}
+	}
+	d.header = header
+	return nil
+}
+
+// CSVEncoder writes tasks as CSV with every column, preceded by the header.
+type CSVEncoder struct {
+	writer        *csv.Writer
+	headerWritten bool
+	record        []string
+}
+
+func NewCSVEncoder(w io.Writer) *CSVEncoder {
+	return &CSVEncoder{writer: csv.NewWriter(w), record: make([]string, len(columns))}
+}
+
+func (e *CSVEncoder) Encode(task dto.Task) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	for i, column := range columns {
+		e.record[i] = column.get(task)
+	}
+	return e.writer.Write(e.record)
+}
+
+// Flush writes the buffered records, and the header if no task was encoded.
+func (e *CSVEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *CSVEncoder) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	for i, column := range columns {
+		e.record[i] = column.name
+	}
+	return e.writer.Write(e.record)
+}
+
+func columnIndex(name string) int {
+	for i, column := range columns {
+		if column.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// decayOf returns the decay of the task to set a decay column to value,
+// creating it unless value is empty, in which case a throwaway decay is
+// returned so that the task keeps no decay.
+func decayOf(task *dto.Task, value string) *dto.Decay {
+	if value == "" {
+		return &dto.Decay{}
+	}
+	if task.Decay == nil {
+		task.Decay = &dto.Decay{}
+	}
+	return task.Decay
+}
+
+func decayField(task dto.Task, get func(decay dto.Decay) string) string {
+	if task.Decay == nil {
+		return ""
+	}
+	return get(*task.Decay)
+}
+
+func parseList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	items := strings.Split(value, ListSeparator)
+	for i, item := range items {
+		items[i] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+func parseFloat(value string, out *float64) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*out = parsed
+	return nil
+}
+
+func parseBool(value string, out *bool) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*out = parsed
+	return nil
+}
+
+func formatFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatUint(value uint64) string {
+	if value == 0 {
+		return ""
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.Format(time.RFC3339Nano)
+}
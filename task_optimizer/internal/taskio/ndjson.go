@@ -0,0 +1,66 @@
+package taskio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"task_optimizer/internal/dto"
+)
+
+// MaxLineSize is the size of the longest NDJSON line that can be decoded.
+const MaxLineSize = 1 << 20
+
+type NDJSONDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewNDJSONDecoder(r io.Reader) *NDJSONDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxLineSize)
+	return &NDJSONDecoder{scanner: scanner}
+}
+
+// Decode reads the task of the next non-blank line. Each line is decoded on
+// its own, so a malformed line doesn't prevent reading the next ones.
+func (d *NDJSONDecoder) Decode() (dto.Task, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var task dto.Task
+		if err := json.Unmarshal(line, &task); err != nil {
+			return dto.Task{}, &RecordError{Line: d.line, Err: err}
+		}
+		return task, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return dto.Task{}, err
+	}
+	return dto.Task{}, io.EOF
+}
+
+func (d *NDJSONDecoder) Line() int {
+	return d.line
+}
+
+type NDJSONEncoder struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	writer := bufio.NewWriter(w)
+	return &NDJSONEncoder{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+func (e *NDJSONEncoder) Encode(task dto.Task) error {
+	return e.encoder.Encode(task)
+}
+
+func (e *NDJSONEncoder) Flush() error {
+	return e.writer.Flush()
+}
@@ -0,0 +1,109 @@
+// Package taskio reads and writes streams of tasks as newline delimited JSON
+// and CSV, one task at a time so that large imports and exports don't have to
+// fit in memory.
+package taskio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"task_optimizer/internal/dto"
+)
+
+const (
+	MediaTypeNDJSON = "application/x-ndjson"
+	MediaTypeCSV    = "text/csv"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Format is a streaming format of tasks.
+type Format int
+
+const (
+	FormatNDJSON Format = iota
+	FormatCSV
+)
+
+// ParseMediaType returns the format of a Content-Type or Accept media type,
+// ignoring its parameters.
+func ParseMediaType(mediaType string) (Format, error) {
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFormat, mediaType)
+	}
+	switch parsed {
+	case MediaTypeNDJSON:
+		return FormatNDJSON, nil
+	case MediaTypeCSV:
+		return FormatCSV, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFormat, parsed)
+	}
+}
+
+// ParseFormat returns the format named ndjson or csv.
+func ParseFormat(name string) (Format, error) {
+	switch name {
+	case "ndjson":
+		return FormatNDJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFormat, name)
+	}
+}
+
+func (f Format) MediaType() string {
+	if f == FormatCSV {
+		return MediaTypeCSV
+	}
+	return MediaTypeNDJSON
+}
+
+// RecordError is an invalid record of a stream. The stream can still be read
+// after it, unlike after any other error.
+type RecordError struct {
+	// Line is the line of the record, starting at 1 and counting the CSV
+	// header.
+	Line int
+	Err  error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Decoder reads the tasks of a stream. Decode returns io.EOF at the end of
+// the stream and a *RecordError for each invalid record, and Line returns
+// the line of the last record decoded.
+type Decoder interface {
+	Decode() (dto.Task, error)
+	Line() int
+}
+
+// Encoder writes tasks to a stream. Flush must be called once all the tasks
+// are encoded.
+type Encoder interface {
+	Encode(task dto.Task) error
+	Flush() error
+}
+
+func NewDecoder(format Format, r io.Reader) Decoder {
+	if format == FormatCSV {
+		return NewCSVDecoder(r)
+	}
+	return NewNDJSONDecoder(r)
+}
+
+func NewEncoder(format Format, w io.Writer) Encoder {
+	if format == FormatCSV {
+		return NewCSVEncoder(w)
+	}
+	return NewNDJSONEncoder(w)
+}
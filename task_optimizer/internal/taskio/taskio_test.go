@@ -0,0 +1,137 @@
+package taskio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"task_optimizer/internal/dto"
+	"testing"
+	"time"
+)
+
+// decodeAll returns the names of the decoded tasks and the lines of the
+// invalid records.
+func decodeAll(t *testing.T, decoder Decoder) ([]string, []int) {
+	t.Helper()
+	var names []string
+	var errorLines []int
+	for {
+		task, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return names, errorLines
+		}
+		var recordErr *RecordError
+		switch {
+		case errors.As(err, &recordErr):
+			errorLines = append(errorLines, recordErr.Line)
+		case err != nil:
+			t.Fatalf("unexpected error %v", err)
+		default:
+			names = append(names, task.Name)
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name           string
+		format         Format
+		input          string
+		wantNames      []string
+		wantErrorLines []int
+	}{
+		{
+			name:      "ndjson skips blank lines",
+			format:    FormatNDJSON,
+			input:     "{\"name\": \"a\"}\n\n  \n{\"name\": \"b\"}",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:           "ndjson reports malformed lines",
+			format:         FormatNDJSON,
+			input:          "{\"name\": \"a\"}\n{\"name\": \n{\"name\": \"c\", \"profit\": \"x\"}\n{\"name\": \"d\"}\n",
+			wantNames:      []string{"a", "d"},
+			wantErrorLines: []int{2, 3},
+		},
+		{
+			name:      "csv columns in any order",
+			format:    FormatCSV,
+			input:     "profit,resources,name\n1,camera,a\n2,\"camera;antenna\",b\n",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:           "csv reports invalid records",
+			format:         FormatCSV,
+			input:          "name,resources,profit\na,camera,1\nb,camera\nc,camera,x\n\"d\nd\",camera,4\ne,camera,5\n",
+			wantNames:      []string{"a", "d\nd", "e"},
+			wantErrorLines: []int{3, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, errorLines := decodeAll(t, NewDecoder(tt.format, strings.NewReader(tt.input)))
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("names = %q, want %q", names, tt.wantNames)
+			}
+			if !reflect.DeepEqual(errorLines, tt.wantErrorLines) {
+				t.Errorf("error lines = %v, want %v", errorLines, tt.wantErrorLines)
+			}
+		})
+	}
+}
+
+func TestCSVDecoder_InvalidHeader(t *testing.T) {
+	for _, input := range []string{"", "name,resources\n", "name,resources,profit,color\n", "name,name,resources,profit\n"} {
+		_, err := NewCSVDecoder(strings.NewReader(input)).Decode()
+		var recordErr *RecordError
+		if err == nil || errors.Is(err, io.EOF) || errors.As(err, &recordErr) {
+			t.Errorf("Decode() of header %q = %v, want a fatal error", input, err)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	expiresAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tasks := []dto.Task{
+		{
+			Name:       "capture, north",
+			Client:     "acme",
+			Resources:  []string{"camera", "antenna.x"},
+			Profit:     12.5,
+			Decay:      &dto.Decay{Type: "exponential", HalfLife: "2h"},
+			Satellites: []string{"sat-1"},
+			Energy:     3,
+			Priority:   "critical",
+			ExpiresAt:  &expiresAt,
+			Held:       true,
+		},
+		{Name: "downlink", Resources: []string{"antenna"}, Profit: 1},
+	}
+	for _, format := range []Format{FormatNDJSON, FormatCSV} {
+		var buffer bytes.Buffer
+		encoder := NewEncoder(format, &buffer)
+		for _, task := range tasks {
+			if err := encoder.Encode(task); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := encoder.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		decoder := NewDecoder(format, &buffer)
+		for _, want := range tasks {
+			got, err := decoder.Decode()
+			if err != nil {
+				t.Fatalf("%s: %v", format.MediaType(), err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: decoded %+v, want %+v", format.MediaType(), got, want)
+			}
+		}
+		if _, err := decoder.Decode(); !errors.Is(err, io.EOF) {
+			t.Errorf("%s: Decode() = %v after the last task, want EOF", format.MediaType(), err)
+		}
+	}
+}
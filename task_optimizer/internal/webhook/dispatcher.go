@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"net/http"
+	"strconv"
+	"sync"
+	"task_optimizer/internal/dto"
+	"task_optimizer/internal/events"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+	"time"
+)
+
+const (
+	// SignatureHeader holds the hex HMAC-SHA256 of the body keyed with the
+	// secret of the webhook, prefixed with "sha256=".
+	SignatureHeader = "X-Signature-256"
+	EventIDHeader   = "X-Event-ID"
+)
+
+type Config struct {
+	// MaxAttempts is the number of delivery attempts before a delivery is
+	// dead-lettered.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled on each
+	// retry up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Timeout bounds each delivery attempt.
+	Timeout time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        10 * time.Second,
+	}
+}
+
+// Dispatcher posts the tasks selected on each execution to the webhooks
+// registered in the service.
+type Dispatcher struct {
+	taskService *service.TaskService
+	client      *http.Client
+	config      Config
+	metrics     *metrics.WebhookMetrics
+	lastEventID uint64
+}
+
+// NewDispatcher returns a dispatcher for the executions completed from now on.
+func NewDispatcher(taskService *service.TaskService, config Config, webhookMetrics *metrics.WebhookMetrics) *Dispatcher {
+	return &Dispatcher{
+		taskService: taskService,
+		client:      &http.Client{Timeout: config.Timeout},
+		config:      config,
+		metrics:     webhookMetrics,
+		lastEventID: taskService.LastEventID(),
+	}
+}
+
+// Run delivers the completed executions until ctx is done, and waits for the
+// pending deliveries to finish or be cancelled. The executions are read from
+// a subscription that is never dropped, so none is missed however many
+// events are published at once.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var deliveries sync.WaitGroup
+	defer deliveries.Wait()
+	subscription, missed := d.taskService.SubscribeCompletedExecutions(d.lastEventID)
+	defer subscription.Close()
+	for _, event := range missed {
+		d.dispatch(ctx, event, &deliveries)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			d.dispatch(ctx, event, &deliveries)
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event events.Event, deliveries *sync.WaitGroup) {
+	d.lastEventID = event.ID
+	plan, ok := event.Data.(model.Plan)
+	if event.Type != events.ExecutionCompleted || !ok {
+		return
+	}
+	payload, err := json.Marshal(dto.WebhookDelivery{
+		EventID:   event.ID,
+		Time:      event.Time,
+		Execution: dto.ExecutionFromModel(plan),
+	})
+	if err != nil {
+		log.Err(err).Uint64("event_id", event.ID).Send()
+		return
+	}
+	for _, webhook := range d.taskService.ListWebhooks() {
+		deliveries.Add(1)
+		go func() {
+			defer deliveries.Done()
+			d.deliver(ctx, webhook, event.ID, payload)
+		}()
+	}
+}
+
+// deliver posts the payload to the webhook retrying with exponential backoff,
+// and dead-letters it when every attempt fails or ctx is done.
+func (d *Dispatcher) deliver(ctx context.Context, webhook model.Webhook, eventID uint64, payload []byte) {
+	logger := log.With().Uint64("webhook_id", webhook.ID).Uint64("event_id", eventID).Logger()
+	backoff := d.config.InitialBackoff
+	var err error
+	attempts := 0
+	for attempts < d.config.MaxAttempts {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(backoff):
+			}
+			if ctx.Err() != nil {
+				// Cancelled deliveries are dead-lettered too, so they are
+				// not lost on shutdown.
+				err = ctx.Err()
+				break
+			}
+			backoff = min(2*backoff, d.config.MaxBackoff)
+		}
+		attempts++
+		startTime := time.Now()
+		// The attempt in flight is not cancelled with ctx, only bounded by
+		// the timeout of the client, so that shutting down doesn't
+		// dead-letter deliveries that are being received.
+		err = d.post(context.WithoutCancel(ctx), webhook, eventID, payload)
+		d.metrics.DeliveryTime.Observe(time.Since(startTime).Seconds())
+		if err == nil {
+			d.metrics.DeliveryAttempts.WithLabelValues("success").Inc()
+			logger.Info().Int("attempts", attempts).Msg("webhook delivered")
+			return
+		}
+		d.metrics.DeliveryAttempts.WithLabelValues("failure").Inc()
+		logger.Err(err).Int("attempt", attempts).Msg("webhook delivery failed")
+	}
+
+	d.metrics.DeadLetters.Inc()
+	d.taskService.AddDeadLetter(model.DeadLetter{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		EventID:   eventID,
+		Payload:   payload,
+		Attempts:  attempts,
+		Error:     fmt.Sprint(err),
+		FailedAt:  time.Now(),
+	})
+	logger.Error().Int("attempts", attempts).Msg("webhook delivery dead-lettered")
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook model.Webhook, eventID uint64, payload []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventIDHeader, strconv.FormatUint(eventID, 10))
+	request.Header.Set(SignatureHeader, Sign(webhook.Secret, payload))
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", response.Status)
+	}
+	return nil
+}
+
+// Sign returns the value of the signature header for the payload.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
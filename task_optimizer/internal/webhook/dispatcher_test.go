@@ -0,0 +1,94 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"task_optimizer/internal/ds/set"
+	"task_optimizer/internal/metrics"
+	"task_optimizer/internal/model"
+	"task_optimizer/internal/service"
+	"testing"
+	"time"
+)
+
+var (
+	taskServiceMetrics = metrics.NewTaskServiceMetricsVec().ForNamespace("default")
+	webhookMetrics     = metrics.NewWebhookMetrics()
+)
+
+func TestDispatcher_Run(t *testing.T) {
+	tests := []struct {
+		name            string
+		failures        int32
+		wantAttempts    int32
+		wantDeadLetters int
+	}{
+		{"delivered", 0, 1, 0},
+		{"delivered after retries", 2, 3, 0},
+		{"dead-lettered", 5, 3, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			received := make(chan []byte, 1)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if r.Header.Get(SignatureHeader) != Sign("secret", body) {
+					t.Errorf("invalid signature %q", r.Header.Get(SignatureHeader))
+				}
+				if attempts.Add(1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				received <- body
+			}))
+			defer receiver.Close()
+
+			taskService := service.NewTaskService(service.DefaultConfig(), taskServiceMetrics)
+			if _, err := taskService.AddWebhook(model.Webhook{URL: receiver.URL, Secret: "secret"}); err != nil {
+				t.Fatal(err)
+			}
+			config := Config{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Timeout: time.Second}
+			dispatcher := NewDispatcher(taskService, config, webhookMetrics)
+			taskService.AddTasks([]model.Task{{Name: "capture", Resources: set.Of("camera"), Profit: 1}}, service.AnyVersion)
+			if _, err := taskService.GetHigherProfitSubset(context.Background(), model.UnconstrainedPlanRequest()); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				dispatcher.Run(ctx)
+				close(done)
+			}()
+			deadline := time.After(5 * time.Second)
+			for tt.wantDeadLetters == 0 && len(received) == 0 || tt.wantDeadLetters > 0 && len(taskService.ListDeadLetters()) == 0 {
+				select {
+				case <-deadline:
+					t.Fatal("delivery not completed")
+				case <-time.After(time.Millisecond):
+				}
+			}
+			cancel()
+			<-done
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if got := len(taskService.ListDeadLetters()); got != tt.wantDeadLetters {
+				t.Errorf("dead letters = %d, want %d", got, tt.wantDeadLetters)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Reference value from the HMAC-SHA256 test vectors of RFC 4231, test case 2.
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got := Sign("Jefe", []byte("what do ya want for nothing?")); got != want {
+		t.Errorf("Sign() = %v, want %v", got, want)
+	}
+}